@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Work with the configuration of the CLI",
+		Long: `The configuration of the CLI is merged from files in the home directory and the current
+		directory, the file specified on the command line, environment variables and flags. These
+		commands help to understand the configuration that is being used`,
+	}
+
+	configShowCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show the merged configuration and where each value came from",
+		Long: `Outputs the configuration that the CLI will use, after all of the configuration files,
+		environment variables and flags have been merged. Each value is annotated with the file and
+		line, environment variable or flag that it came from, or default if it has not been set`,
+		Run:         executeConfigShow,
+		Annotations: map[string]string{offlineAnnotation: "true"},
+	}
+)
+
+func init() {
+
+	// declare variables that will be used to hold information from the supplied
+	// arguments
+	var format string
+
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+
+	// Configure the flags
+	configShowCmd.Flags().StringVar(&format, "format", "yaml", "Format of the output, yaml or json")
+}
+
+func executeConfigShow(ccmd *cobra.Command, args []string) {
+
+	provenance := config.NewProvenance()
+
+	// add the files in the order in which they were merged
+	for _, layer := range mergedConfigFiles {
+		err := provenance.AddFileSection(layer.File, layer.Section)
+		if err != nil {
+			App.Logger.Warnf("Unable to determine the values set in file: %s", err.Error())
+		}
+	}
+
+	// add the environment variables that are set for known keys, using the same prefix
+	// and replacer that viper uses to read them
+	for _, key := range viper.AllKeys() {
+		name := strings.ToUpper(fmt.Sprintf("%s_%s", envVarPrefix, envKeyReplacer.Replace(key)))
+		if _, ok := os.LookupEnv(name); ok {
+			provenance.AddEnv(key, name)
+		}
+	}
+
+	// add the flags that have been set on the command line
+	for key, flag := range flagBindings {
+		if flag.Changed {
+			provenance.AddFlag(key, fmt.Sprintf("--%s", flag.Name))
+		}
+	}
+
+	var data []byte
+	var err error
+
+	format, _ := ccmd.Flags().GetString("format")
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		data, err = provenance.YAML(Config.Input)
+	case "json":
+		data, err = provenance.JSON(Config.Input)
+	default:
+		err = fmt.Errorf("format '%s' is not supported, supported formats are yaml and json", format)
+	}
+
+	if err != nil {
+		App.Log("GEN001", "fatal", "config show", err.Error())
+	}
+
+	fmt.Println(string(data))
+}
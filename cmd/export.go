@@ -4,7 +4,6 @@ import (
 	"github.com/Ensono/stacks-cli/internal/util"
 	"github.com/Ensono/stacks-cli/pkg/export"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 var (
@@ -30,7 +29,7 @@ func init() {
 	// Configure the flags
 	exportCmd.Flags().StringVarP(&directory, "directory", "d", util.GetDefaultWorkingDir(), "Directory to be used to export the files to")
 
-	viper.BindPFlag("input.directory.export", exportCmd.Flags().Lookup("directory"))
+	bindFlag("input.directory.export", exportCmd.Flags().Lookup("directory"))
 }
 
 func executeExportFiles(ccmd *cobra.Command, args []string) {
@@ -40,7 +39,7 @@ func executeExportFiles(ccmd *cobra.Command, args []string) {
 	err := export.Run()
 	if err != nil {
 		msg := App.Help.GetMessage("GEN001", "export", err.Error())
-		App.Logger.Fatalf(msg)
+		App.Logger.Fatal(msg)
 	}
 
 }
@@ -5,17 +5,17 @@ import (
 	"log"
 	"os"
 	"path"
-	"path/filepath"
-	"runtime"
-	"slices"
+	"sort"
 	"strings"
 
 	"github.com/Ensono/stacks-cli/internal/config/staticFiles"
 	"github.com/Ensono/stacks-cli/internal/constants"
+	"github.com/Ensono/stacks-cli/internal/httpclient"
 	"github.com/Ensono/stacks-cli/internal/models"
 	"github.com/Ensono/stacks-cli/internal/util"
 	"github.com/Ensono/stacks-cli/pkg/config"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v2"
 )
@@ -33,6 +33,23 @@ var (
 	// define a slice to hold a list of all the configuration files that have been read in
 	ConfigFiles []string
 
+	// mergedConfigFiles holds the configuration files, and sections of files, that have been
+	// merged into viper, in the order in which they were merged
+	mergedConfigFiles []configLayer
+
+	// profileErr is set if the selected profile cannot be found, it is reported once the
+	// command is known so that profiles can still be managed
+	profileErr error
+
+	// envVarPrefix and envKeyReplacer are used by viper to find the environment variable
+	// for a configuration key, so that the source of a value can be shown
+	envVarPrefix   string
+	envKeyReplacer *strings.Replacer
+
+	// flagBindings holds the flag that is bound to each configuration key, so that
+	// the source of a value can be shown
+	flagBindings = map[string]*pflag.Flag{}
+
 	// Set a variable to hold the version number of the application
 	version string
 
@@ -43,8 +60,22 @@ var (
 	terraform_backend_group     string
 	terraform_backend_container string
 	global                      bool
+
+	// values to set in the configuration by their path, from the `--set` flag
+	setValues []string
 )
 
+// offlineAnnotation is set on commands that only work with local files, so that the
+// connectivity and CLI version checks are not performed for them
+const offlineAnnotation = "offline"
+
+// configLayer is a configuration file that has been merged into viper. If the section is set
+// only the values in that section of the file have been merged, e.g. `profiles.clienta`
+type configLayer struct {
+	File    string
+	Section string
+}
+
 var rootCmd = &cobra.Command{
 	Use:     "stacks-cli",
 	Short:   "Build up a new project based on the Ensono Stacks system",
@@ -81,11 +112,22 @@ func init() {
 	var dryrun bool
 
 	var githubToken string
+	var githubHost string
+	var githubAPI string
+
+	var caBundle string
+	var timeout int
+	var retries int
+	var maxArchiveSize int
+	var maxExtractSize int
+	var maxExtractFiles int
 
 	var override_internal_config string
 
 	var folders []string
 
+	var profile string
+
 	cobra.OnInitialize(initConfig)
 
 	// get the default directories
@@ -109,11 +151,21 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&noCLIVersionCheck, "nocliversion", false, "Do not check for latest version of the CLI")
 	rootCmd.PersistentFlags().BoolVarP(&onlineHelp, "onlinehelp", "H", false, "Open web browser with help for the command")
 
-	rootCmd.PersistentFlags().StringVar(&githubToken, "token", "", "GitHub token to perform authenticated requests against the GitHub API")
+	rootCmd.PersistentFlags().StringVar(&githubToken, "token", "", "GitHub token to perform authenticated requests against the GitHub API, or a reference to it such as env:GITHUB_TOKEN, file:<path> or cmd:<command>")
+	rootCmd.PersistentFlags().StringVar(&githubHost, "githubhost", "", "Host of the GitHub Enterprise Server to use instead of github.com")
+	rootCmd.PersistentFlags().StringVar(&githubAPI, "githubapi", "", "Base URL of the GitHub API, if not set it is derived from the GitHub host")
+
+	rootCmd.PersistentFlags().StringVar(&caBundle, "ca-bundle", "", "Path to a PEM file of additional certificate authorities to trust for network requests")
+	rootCmd.PersistentFlags().IntVar(&timeout, "timeout", 30, "Number of seconds allowed to connect to a server and receive a response")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 3, "Number of times a failed network request is retried")
+	rootCmd.PersistentFlags().IntVar(&maxArchiveSize, "maxarchivesize", 1024, "Maximum size, in MB, of a package archive that can be downloaded")
+	rootCmd.PersistentFlags().IntVar(&maxExtractSize, "maxextractsize", 4096, "Maximum size, in MB, of the files extracted from a package archive")
+	rootCmd.PersistentFlags().IntVar(&maxExtractFiles, "maxextractfiles", 100000, "Maximum number of files that can be extracted from a package archive")
 
 	rootCmd.PersistentFlags().StringVar(&override_internal_config, "internalconfig", "", "Path to the configuration override file")
 
 	rootCmd.PersistentFlags().StringSliceVar(&folders, "folders", []string{}, "List of additional folders to be used when running setup")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Name of the profile in the global configuration file to use")
 
 	rootCmd.PersistentFlags().StringVar(&business_company, "company", "", "The name of the company")
 	rootCmd.PersistentFlags().StringVarP(&business_domain, "area", "A", "", "Area within the company that this project will belong to, e.g. core")
@@ -126,31 +178,50 @@ func init() {
 	viper.BindPFlags(rootCmd.Flags())
 
 	// Configure the logging options
-	viper.BindPFlag("input.log.format", rootCmd.PersistentFlags().Lookup("logformat"))
-	viper.BindPFlag("input.log.colour", rootCmd.PersistentFlags().Lookup("logcolour"))
-	viper.BindPFlag("input.log.level", rootCmd.PersistentFlags().Lookup("loglevel"))
-	viper.BindPFlag("input.log.file", rootCmd.PersistentFlags().Lookup("logfile"))
-
-	viper.BindPFlag("input.directory.working", rootCmd.PersistentFlags().Lookup("workingdir"))
-	viper.BindPFlag("input.directory.temp", rootCmd.PersistentFlags().Lookup("tempdir"))
-	viper.BindPFlag("input.directory.home", rootCmd.PersistentFlags().Lookup("homedir"))
-
-	viper.BindPFlag("input.options.nobanner", rootCmd.PersistentFlags().Lookup("nobanner"))
-	viper.BindPFlag("input.options.nocliversion", rootCmd.PersistentFlags().Lookup("nocliversion"))
-	viper.BindPFlag("input.options.onlinehelp", rootCmd.PersistentFlags().Lookup("onlinehelp"))
-	viper.BindPFlag("input.options.dryrun", rootCmd.PersistentFlags().Lookup("dryrun"))
-
-	viper.BindPFlag("input.overrides.internal_config", rootCmd.PersistentFlags().Lookup("internalconfig"))
-	viper.BindPFlag("input.folders", rootCmd.PersistentFlags().Lookup("folders"))
-
-	viper.BindPFlag("input.business.company", rootCmd.PersistentFlags().Lookup("company"))
-	viper.BindPFlag("input.business.domain", rootCmd.PersistentFlags().Lookup("area"))
-
-	viper.BindPFlag("input.terraform.backend.storage", rootCmd.PersistentFlags().Lookup("tfstorage"))
-	viper.BindPFlag("input.terraform.backend.group", rootCmd.PersistentFlags().Lookup("tfgroup"))
-	viper.BindPFlag("input.terraform.backend.container", rootCmd.PersistentFlags().Lookup("tfcontainer"))
+	bindFlag("input.log.format", rootCmd.PersistentFlags().Lookup("logformat"))
+	bindFlag("input.log.colour", rootCmd.PersistentFlags().Lookup("logcolour"))
+	bindFlag("input.log.level", rootCmd.PersistentFlags().Lookup("loglevel"))
+	bindFlag("input.log.file", rootCmd.PersistentFlags().Lookup("logfile"))
+
+	bindFlag("input.directory.working", rootCmd.PersistentFlags().Lookup("workingdir"))
+	bindFlag("input.directory.temp", rootCmd.PersistentFlags().Lookup("tempdir"))
+	bindFlag("input.directory.home", rootCmd.PersistentFlags().Lookup("homedir"))
+
+	bindFlag("input.options.nobanner", rootCmd.PersistentFlags().Lookup("nobanner"))
+	bindFlag("input.options.nocliversion", rootCmd.PersistentFlags().Lookup("nocliversion"))
+	bindFlag("input.options.onlinehelp", rootCmd.PersistentFlags().Lookup("onlinehelp"))
+	bindFlag("input.options.dryrun", rootCmd.PersistentFlags().Lookup("dryrun"))
+	bindFlag("input.options.token", rootCmd.PersistentFlags().Lookup("token"))
+	bindFlag("input.options.githubhost", rootCmd.PersistentFlags().Lookup("githubhost"))
+	bindFlag("input.options.githubapi", rootCmd.PersistentFlags().Lookup("githubapi"))
+	bindFlag("input.options.cabundle", rootCmd.PersistentFlags().Lookup("ca-bundle"))
+	bindFlag("input.options.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	bindFlag("input.options.retries", rootCmd.PersistentFlags().Lookup("retries"))
+	bindFlag("input.options.maxarchivesize", rootCmd.PersistentFlags().Lookup("maxarchivesize"))
+	bindFlag("input.options.maxextractsize", rootCmd.PersistentFlags().Lookup("maxextractsize"))
+	bindFlag("input.options.maxextractfiles", rootCmd.PersistentFlags().Lookup("maxextractfiles"))
+
+	bindFlag("input.overrides.internal_config", rootCmd.PersistentFlags().Lookup("internalconfig"))
+	bindFlag("input.folders", rootCmd.PersistentFlags().Lookup("folders"))
+	bindFlag(constants.ProfileKey, rootCmd.PersistentFlags().Lookup("profile"))
+
+	bindFlag("input.business.company", rootCmd.PersistentFlags().Lookup("company"))
+	bindFlag("input.business.domain", rootCmd.PersistentFlags().Lookup("area"))
+
+	bindFlag("input.terraform.backend.storage", rootCmd.PersistentFlags().Lookup("tfstorage"))
+	bindFlag("input.terraform.backend.group", rootCmd.PersistentFlags().Lookup("tfgroup"))
+	bindFlag("input.terraform.backend.container", rootCmd.PersistentFlags().Lookup("tfcontainer"))
+
+	bindFlag("input.global", rootCmd.PersistentFlags().Lookup("global"))
+}
 
-	viper.BindPFlag("input.global", rootCmd.PersistentFlags().Lookup("global"))
+// bindFlag binds the flag to the configuration key and records the binding so that it is
+// known which flag a value came from
+func bindFlag(key string, flag *pflag.Flag) error {
+	if flag != nil {
+		flagBindings[key] = flag
+	}
+	return viper.BindPFlag(key, flag)
 }
 
 // initConfig reads in a config file and ENV vars if set
@@ -163,53 +234,45 @@ func initConfig() {
 	stacksCliHome := util.GetStacksCLIDir()
 	util.CreateIfNotExists(stacksCliHome, 0755)
 
-	// determine the root path based on the operating system
-	root_path := "/"
-	if runtime.GOOS == "windows" {
-		root_path = fmt.Sprintf("%s\\", os.Getenv("SystemDrive"))
+	// Determine if an environment variable has been set that states the string to be
+	// used as the environment variable prefix
+	// This has been done so that the it is possible for people to set a different value and be
+	// compatible with older version of the Stacks CLI
+	envVarPrefix = constants.EnvVarPrefix
+	if os.Getenv("STACKSCLI_ENVVARPREFIX") != "" {
+		envVarPrefix = os.Getenv("STACKSCLI_ENVVARPREFIX")
 	}
 
-	// get a list of the paths from the cwd
-	var directories []string
-	for _path := util.GetDefaultWorkingDir(); _path != root_path; _path = filepath.Dir(_path) {
-		directories = append(directories, util.NormalisePath(_path, string(os.PathSeparator)))
-	}
-	slices.Reverse(directories)
+	// Allow configuration options to be set using Environment variables
+	// This is done before the files are read so that the profile can be selected using
+	// an environment variable
+	viper.SetEnvPrefix(envVarPrefix)
 
-	// if additional folders have been specified on the commandline add them to the list
-	folders := viper.GetStringSlice("input.folders")
-	if len(folders) > 0 {
-		directories = append(directories, folders...)
-	}
+	// The configuration settings are nested
+	// Change the `.` delimiter to a `_` when accessing from an Environment Variable
+	envKeyReplacer = strings.NewReplacer(".", "_")
+	viper.SetEnvKeyReplacer(envKeyReplacer)
+
+	viper.AutomaticEnv() // read in environment variables that match
 
 	// set multiple paths that a configuration file can be read from
 	// - home directory file
+	// - the selected profile from the home directory file
 	// - all folders from the root to the current directory, this is so that the closest configuration
 	//   is the one that is read in last
 	// - any additional paths that have been specified on the command line
-	homeDirConfigfile := fmt.Sprintf("%s.yml", path.Join(util.GetUserHomeDir(), constants.ConfigFileDir, constants.ConfigName))
-	if util.Exists(homeDirConfigfile) {
-		ConfigFiles = append(ConfigFiles, util.NormalisePath(homeDirConfigfile, string(os.PathSeparator)))
-		viper.SetConfigName(constants.ConfigName)
-		viper.AddConfigPath(path.Join(util.GetUserHomeDir(), constants.ConfigFileDir))
-		viper.MergeInConfig()
-	}
-
-	// reverse the order of the directories so that the closest one is read in last
-	for i := len(directories) - 1; i >= 0; i-- {
-		// build up the path to a possible configuration file and check if it exists
-		configfile := util.NormalisePath(fmt.Sprintf("%s.yml", path.Join(directories[i], constants.ConfigFileDir, constants.ConfigName)), string(os.PathSeparator))
+	folders := viper.GetStringSlice("input.folders")
+	for i, configfile := range util.GetConfigFilePaths(util.GetUserHomeDir(), util.GetDefaultWorkingDir(), folders) {
 		if util.Exists(configfile) {
 
-			if !util.SliceContains(ConfigFiles, configfile) {
-				ConfigFiles = append(ConfigFiles, configfile)
-			}
-
-			// add the config path to the viper instance
-			viper.AddConfigPath(path.Join(directories[i], constants.ConfigFileDir))
-
 			// merge the configuration file into the viper instance
-			viper.MergeInConfig()
+			mergeConfigFile(configfile)
+		}
+
+		// the profile overrides the values in the home directory file, but not those in
+		// the directories
+		if i == 0 {
+			mergeProfile(configfile)
 		}
 	}
 
@@ -219,49 +282,73 @@ func initConfig() {
 		cfgFile = util.NormalisePath(cfgFile, string(os.PathSeparator))
 		// if the cfgfile can be found, copy it to the tempdir
 		if util.Exists(cfgFile) {
+			mergeConfigFile(cfgFile)
+		}
+	}
+
+	// Read  in the static configuration
+	// viper.SetConfigType("yaml")
+	// viper.MergeConfig(strings.NewReader(Config.Internal.GetFileContentString("stacks_frameworks")))
+}
 
-			ConfigFiles = append(ConfigFiles, cfgFile)
+// mergeConfigFile merges the configuration file into the viper instance and adds it to the
+// list of files that have been read. The file is set explicitly so that each file is merged
+// in turn, rather than the first file that is found on the configuration paths
+func mergeConfigFile(path string) {
 
-			// get the name of the configuration file and add it to the configuration path
-			filename := filepath.Base(cfgFile)
-			viper.SetConfigName(strings.TrimSuffix(filename, filepath.Ext(filename)))
-			viper.AddConfigPath(filepath.Dir(cfgFile))
-			viper.MergeInConfig()
+	ConfigFiles = append(ConfigFiles, path)
+	mergedConfigFiles = append(mergedConfigFiles, configLayer{File: path})
 
-			// reset the name of the configuration file
-			viper.SetConfigName(constants.ConfigName)
-		}
+	viper.SetConfigFile(path)
+	err := viper.MergeInConfig()
+	if err != nil {
+		fmt.Printf("Unable to read in configuration file, %s: %s\n", path, err.Error())
 	}
+}
 
-	// Determine if an environment variable has been set that states the string to be
-	// used as the environment variable prefix
-	// This has been done so that the it is possible for people to set a different value and be
-	// compatible with older version of the Stacks CLI
-	envvarprefix := constants.EnvVarPrefix
-	if os.Getenv("STACKSCLI_ENVVARPREFIX") != "" {
-		envvarprefix = os.Getenv("STACKSCLI_ENVVARPREFIX")
+// mergeProfile merges the values of the selected profile from the global configuration file
+// into the viper instance. The profile is selected with the `--profile` flag, the `<prefix>_PROFILE`
+// environment variable, e.g. `ENSONOSTACKS_PROFILE`, or the `profile` setting in the global configuration file
+func mergeProfile(path string) {
+
+	name := viper.GetString(constants.ProfileKey)
+	if name == "" {
+		return
 	}
 
-	// Allow configuration options to be set using Environment variables
-	viper.SetEnvPrefix(envvarprefix)
+	section := fmt.Sprintf("%s.%s", constants.ProfilesKey, strings.ToLower(name))
+	values := viper.GetStringMap(section)
+	if len(values) == 0 {
+		profileErr = fmt.Errorf("profile '%s' does not exist in %s", name, path)
+		return
+	}
 
-	// The configuration settings are nested
-	// Change the `.` delimiter to a `_` when accessing from an Environment Variable
-	replacer := strings.NewReplacer(".", "_")
-	viper.SetEnvKeyReplacer(replacer)
+	err := viper.MergeConfigMap(values)
+	if err != nil {
+		profileErr = fmt.Errorf("unable to read profile '%s': %s", name, err.Error())
+		return
+	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	ConfigFiles = append(ConfigFiles, fmt.Sprintf("%s (profile: %s)", path, name))
+	mergedConfigFiles = append(mergedConfigFiles, configLayer{File: path, Section: section})
+}
 
-	// Read  in the static configuration
-	// viper.SetConfigType("yaml")
-	// viper.MergeConfig(strings.NewReader(Config.Internal.GetFileContentString("stacks_frameworks")))
+// setConfigValues sets the values that have been specified with the `--set` flag in the
+// configuration and returns the paths that have been set
+func setConfigValues() ([]string, error) {
 
-	err := viper.MergeInConfig()
-	if err != nil && viper.ConfigFileUsed() != "" {
-		fmt.Printf("Unable to read in configuration file: %s", err.Error())
-		return
+	values, err := util.ParseKeyValues(setValues)
+	if err != nil {
+		return nil, err
 	}
 
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, Config.SetValues(values)
 }
 
 func preRun(ccmd *cobra.Command, args []string) {
@@ -302,13 +389,26 @@ func preRun(ccmd *cobra.Command, args []string) {
 
 	ScaffoldOverrides()
 
+	// the profile commands must run when the profile cannot be found so that it can be changed
+	if profileErr != nil && ccmd.Parent() != setupProfileCmd {
+		log.Fatalf("Unable to use profile: %s", profileErr.Error())
+		App.Logger.Exit(4)
+	}
+
 	// Unmarshal the configuration into the models in the application
-	err = viper.Unmarshal(&Config)
-	if err != nil {
+	err = viper.Unmarshal(&Config, config.DecodeHook())
+	// the validate command reports the problems in the configuration file itself
+	if err != nil && ccmd != validateCmd {
 		log.Fatalf("Unable to read configuration into models: %v", err)
 		App.Logger.Exit(4)
 	}
 
+	// plain text secrets are registered straight away so that they are always redacted,
+	// references are registered when they are resolved
+	if !Config.Input.Options.Token.IsReference() {
+		Config.Input.Options.Token.Resolve()
+	}
+
 	// Set the help messages in the App.Help object
 	App.LoadHelp(Config.Internal.GetFileContent("help"))
 
@@ -317,15 +417,36 @@ func preRun(ccmd *cobra.Command, args []string) {
 	// model values can be used rather than the strings from viper
 	App.ConfigureLogging(Config.Input.Log)
 
+	// Configure the client that is used for all network requests
+	// progress of downloads is not shown when logging as JSON so that the output can be parsed
+	err = httpclient.Configure(httpclient.Options{
+		Timeout:         Config.Input.Options.GetTimeout(),
+		Retries:         Config.Input.Options.Retries,
+		CABundle:        Config.Input.Options.CABundle,
+		Version:         version,
+		Logger:          App.Logger,
+		MaxDownloadSize: Config.Input.Options.GetMaxArchiveSize(),
+		Progress:        Config.Input.Log.Format != "json",
+	})
+	if err != nil {
+		App.Logger.Fatalf("Unable to configure network client: %s", err.Error())
+		return
+	}
+
+	// set the limits for extracting package archives
+	util.SetExtractLimits(Config.Input.Options.GetExtractLimits())
+
 	// Check that the CLI is online
 	// use a DNS lookup to check that github can be accessed
 	// this is so that the check is not performed if the the environment is not
 	// connected to the internet
-	App.Logger.Info("Performing connectivity check")
-	err = util.CheckConnectivity("github.com")
-	if err != nil {
-		App.Logger.Fatal(err.Error())
-		return
+	if !isOffline(ccmd) {
+		App.Logger.Info("Performing connectivity check")
+		err = util.CheckConnectivity(Config.Input.Options.GetGitHubHost())
+		if err != nil {
+			App.Logger.Fatal(err.Error())
+			return
+		}
 	}
 
 	// Set the version of the app in the configuration
@@ -338,7 +459,7 @@ func preRun(ccmd *cobra.Command, args []string) {
 
 	if overrideConfig != "" {
 		msg := App.Help.GetMessage("INT001", overrideConfig)
-		App.Logger.Infof(msg)
+		App.Logger.Info(msg)
 
 		// add in the internal configuration file to the ConfigFiles slice
 		ConfigFiles = append([]string{overrideConfig}, ConfigFiles...)
@@ -367,7 +488,9 @@ func preRun(ccmd *cobra.Command, args []string) {
 	}
 
 	// Call method to determine if this version of the CLI is the latest one
-	checkCLIVersion()
+	if !isOffline(ccmd) {
+		checkCLIVersion()
+	}
 
 	// output a list of the configuration files that have been read in
 	App.Logger.Infof("Configuration files read:\n\t%s", strings.Join(ConfigFiles, "\n\t"))
@@ -379,6 +502,13 @@ func preRun(ccmd *cobra.Command, args []string) {
 	//}
 }
 
+// isOffline states if the command only works with local files and so does not need
+// to contact GitHub
+func isOffline(ccmd *cobra.Command) bool {
+	_, ok := ccmd.Annotations[offlineAnnotation]
+	return ok
+}
+
 func checkCLIVersion() {
 
 	// do not perform version check if it has been turned off
@@ -387,10 +517,23 @@ func checkCLIVersion() {
 		return
 	}
 
+	// the releases of the CLI are published on github.com, so do not send the request
+	// and token for a GitHub Enterprise Server to the public API
+	if Config.Input.Options.IsGitHubEnterprise() {
+		App.Logger.Debug("Skipping CLI version check as a GitHub Enterprise Server host has been set")
+		return
+	}
+
 	App.Logger.Info("Checking for latest version of CLI")
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", constants.GitHubRef)
-	releaseMap, err := util.CallHTTPAPI(url, Config.Input.Options.Token)
+	token, err := Config.Input.Options.Token.Resolve()
+	if err != nil {
+		App.Logger.Errorf("Unable to get latest CLI version: %s", err.Error())
+		return
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/releases/latest", util.GitHubAPIBase(util.GitHubHost), constants.GitHubRef)
+	releaseMap, err := util.CallHTTPAPI(url, token)
 
 	if err != nil {
 		App.Logger.Errorf("Unable to get latest CLI version: %s", err.Error())
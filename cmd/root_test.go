@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestConfig writes the configuration file into the `.stackscli` directory of the dir
+func writeTestConfig(t *testing.T, dir string, content string) {
+	path := filepath.Join(dir, ".stackscli", "config.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestInitConfigProfile(t *testing.T) {
+
+	home := t.TempDir()
+	workingDir := t.TempDir()
+
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Chdir(workingDir)
+
+	// the profile overrides the company, but not the domain, from the global file
+	writeTestConfig(t, home, `input:
+  business:
+    company: homeco
+    domain: homedomain
+profile: clienta
+profiles:
+  clienta:
+    input:
+      business:
+        company: clientaco
+`)
+
+	defer viper.Reset()
+
+	initConfig()
+
+	require.NoError(t, profileErr)
+	assert.Equal(t, "clientaco", viper.GetString("input.business.company"), "The profile should override the global configuration file")
+	assert.Equal(t, "homedomain", viper.GetString("input.business.domain"), "Values that are not in the profile should be read from the global configuration file")
+
+	// the directory configuration file overrides the profile
+	viper.Reset()
+	ConfigFiles = nil
+	writeTestConfig(t, workingDir, `input:
+  business:
+    company: dirco
+`)
+
+	initConfig()
+
+	assert.Equal(t, "dirco", viper.GetString("input.business.company"), "The directory configuration file should override the profile")
+}
+
+func TestIsOffline(t *testing.T) {
+
+	// commands that only work with local files do not need to contact GitHub
+	assert.True(t, isOffline(schemaCmd))
+	assert.True(t, isOffline(validateCmd))
+	assert.True(t, isOffline(configShowCmd))
+
+	assert.False(t, isOffline(scaffoldCmd))
+}
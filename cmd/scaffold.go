@@ -29,6 +29,7 @@ func init() {
 	var nocleanup bool
 	var force bool
 	var noscaffold bool
+	var noverify bool
 
 	// - scaffold directories
 	var cacheDir string
@@ -44,6 +45,7 @@ func init() {
 	var framework_option string
 	var framework_version string
 	var framework_properties []string
+	var framework_property_values []string
 	var framework_deployment_mode string
 
 	// - platform settings
@@ -67,6 +69,9 @@ func init() {
 	// - overrides
 	var override_ado_variables string
 
+	// - variables
+	var variables []string
+
 	// get the default directories
 	defaultCacheDir := util.GetDefaultCacheDir()
 
@@ -87,6 +92,7 @@ func init() {
 
 	// get the properties from the command line
 	scaffoldCmd.Flags().StringSliceVar(&framework_properties, "frameworkprops", []string{}, "Properties to pass to the project settings")
+	scaffoldCmd.Flags().StringArrayVar(&framework_property_values, "property", []string{}, "Property to add to the framework properties of the projects, in the form key=value. Can be specified multiple times")
 	scaffoldCmd.Flags().StringVar(&framework_deployment_mode, "framework_deployment_mode", "AKS", "How containers will be deployed")
 
 	scaffoldCmd.Flags().StringVarP(&platform_type, "platformtype", "P", "", "Type of platform being deployed to")
@@ -104,55 +110,60 @@ func init() {
 
 	scaffoldCmd.Flags().StringVar(&override_ado_variables, "adovariables", "", "Path to the ado variables override file")
 
+	scaffoldCmd.Flags().StringArrayVar(&variables, "var", []string{}, "Variable to make available to templates, in the form key=value. Can be specified multiple times")
+	scaffoldCmd.Flags().StringArrayVar(&setValues, "set", []string{}, "Set a value in the configuration, in the form path=value, e.g. input.project[1].cloud.region=westeurope. Can be specified multiple times")
+
 	scaffoldCmd.Flags().BoolVar(&cmdlog, "cmdlog", false, "Specify if commands should be logged")
 	scaffoldCmd.Flags().BoolVar(&saveConfig, "save", false, "Save the the configuration from interactive or command line settings. Has no effect when using a configuration file.")
 	scaffoldCmd.Flags().BoolVar(&nocleanup, "nocleanup", false, "If set, do not perform cleanup at the end of the scaffolding")
 	scaffoldCmd.Flags().BoolVar(&force, "force", false, "If set, remove existing project directories before attempting to create new ones")
 	scaffoldCmd.Flags().BoolVar(&noscaffold, "noscaffold", false, "When used in conjunction with --save, will not attempt to scaffold the projects but will just create config file")
+	scaffoldCmd.Flags().BoolVar(&noverify, "noverify", false, "If set, use Nuget packages when the hash to verify them cannot be retrieved")
 
 	// Bind the flags to the configuration
 
 	// The project is a slice, so that multiple projects can be specified, however
 	// only one can be specified on the command line and in Environment variables
 	// Viper works out that this is a slice and will bind to the first element of the slice
-	viper.BindPFlag("input.project.name", scaffoldCmd.Flags().Lookup("name"))
-	viper.BindPFlag("input.project.platform.type", scaffoldCmd.Flags().Lookup("platformtype"))
-	viper.BindPFlag("input.project.sourcecontrol.type", scaffoldCmd.Flags().Lookup("sourcecontrol"))
-	viper.BindPFlag("input.project.sourcecontrol.url", scaffoldCmd.Flags().Lookup("sourcecontrolurl"))
+	bindFlag("input.project.name", scaffoldCmd.Flags().Lookup("name"))
+	bindFlag("input.project.platform.type", scaffoldCmd.Flags().Lookup("platformtype"))
+	bindFlag("input.project.sourcecontrol.type", scaffoldCmd.Flags().Lookup("sourcecontrol"))
+	bindFlag("input.project.sourcecontrol.url", scaffoldCmd.Flags().Lookup("sourcecontrolurl"))
 
-	viper.BindPFlag("input.project.settingsfile", scaffoldCmd.Flags().Lookup("projectsettingsfile"))
-	viper.BindPFlag("input.project.cloud.region", scaffoldCmd.Flags().Lookup("cloudregion"))
-	viper.BindPFlag("input.project.cloud.group", scaffoldCmd.Flags().Lookup("cloudgroup"))
+	bindFlag("input.project.settingsfile", scaffoldCmd.Flags().Lookup("projectsettingsfile"))
+	bindFlag("input.project.cloud.region", scaffoldCmd.Flags().Lookup("cloudregion"))
+	bindFlag("input.project.cloud.group", scaffoldCmd.Flags().Lookup("cloudgroup"))
 
 	// configure the project framework settings
-	viper.BindPFlag("input.project.framework.type", scaffoldCmd.Flags().Lookup("framework"))
-	viper.BindPFlag("input.project.framework.option", scaffoldCmd.Flags().Lookup("frameworkoption"))
-	viper.BindPFlag("input.project.framework.version", scaffoldCmd.Flags().Lookup("frameworkversion"))
-	viper.BindPFlag("input.project.framework.deployment_mode", scaffoldCmd.Flags().Lookup("framework_deployment_mode"))
+	bindFlag("input.project.framework.type", scaffoldCmd.Flags().Lookup("framework"))
+	bindFlag("input.project.framework.option", scaffoldCmd.Flags().Lookup("frameworkoption"))
+	bindFlag("input.project.framework.version", scaffoldCmd.Flags().Lookup("frameworkversion"))
+	bindFlag("input.project.framework.deployment_mode", scaffoldCmd.Flags().Lookup("framework_deployment_mode"))
 
 	// -- bind the framework properties to the project framework
-	viper.BindPFlag("input.project.framework.properties", scaffoldCmd.Flags().Lookup("frameworkprops"))
+	bindFlag("input.project.framework.properties", scaffoldCmd.Flags().Lookup("frameworkprops"))
 
-	viper.BindPFlag("input.settingsfile", scaffoldCmd.Flags().Lookup("settingsfile"))
+	bindFlag("input.settingsfile", scaffoldCmd.Flags().Lookup("settingsfile"))
 
-	viper.BindPFlag("input.pipeline", scaffoldCmd.Flags().Lookup("pipeline"))
+	bindFlag("input.pipeline", scaffoldCmd.Flags().Lookup("pipeline"))
 
-	viper.BindPFlag("input.cloud.platform", scaffoldCmd.Flags().Lookup("cloud"))
+	bindFlag("input.cloud.platform", scaffoldCmd.Flags().Lookup("cloud"))
 
-	viper.BindPFlag("input.business.component", scaffoldCmd.Flags().Lookup("component"))
+	bindFlag("input.business.component", scaffoldCmd.Flags().Lookup("component"))
 
-	viper.BindPFlag("input.network.base.domain.external", scaffoldCmd.Flags().Lookup("domain"))
-	viper.BindPFlag("input.network.base.domain.internal", scaffoldCmd.Flags().Lookup("internaldomain"))
+	bindFlag("input.network.base.domain.external", scaffoldCmd.Flags().Lookup("domain"))
+	bindFlag("input.network.base.domain.internal", scaffoldCmd.Flags().Lookup("internaldomain"))
 
-	viper.BindPFlag("input.directory.cache", scaffoldCmd.Flags().Lookup("cachedir"))
+	bindFlag("input.directory.cache", scaffoldCmd.Flags().Lookup("cachedir"))
 
-	viper.BindPFlag("input.overrides.ado_variables_path", scaffoldCmd.Flags().Lookup("adovariables"))
+	bindFlag("input.overrides.ado_variables_path", scaffoldCmd.Flags().Lookup("adovariables"))
 
-	viper.BindPFlag("input.options.cmdlog", scaffoldCmd.Flags().Lookup("cmdlog"))
-	viper.BindPFlag("input.options.save", scaffoldCmd.Flags().Lookup("save"))
-	viper.BindPFlag("input.options.nocleanup", scaffoldCmd.Flags().Lookup("nocleanup"))
-	viper.BindPFlag("input.options.force", scaffoldCmd.Flags().Lookup("force"))
-	viper.BindPFlag("input.options.noscaffold", scaffoldCmd.Flags().Lookup("noscaffold"))
+	bindFlag("input.options.cmdlog", scaffoldCmd.Flags().Lookup("cmdlog"))
+	bindFlag("input.options.save", scaffoldCmd.Flags().Lookup("save"))
+	bindFlag("input.options.nocleanup", scaffoldCmd.Flags().Lookup("nocleanup"))
+	bindFlag("input.options.force", scaffoldCmd.Flags().Lookup("force"))
+	bindFlag("input.options.noscaffold", scaffoldCmd.Flags().Lookup("noscaffold"))
+	bindFlag("input.options.noverify", scaffoldCmd.Flags().Lookup("noverify"))
 }
 
 // ScaffoldOverrides updates the main configuration with any override files that have been specified on
@@ -199,15 +210,43 @@ func executeScaffoldRun(ccmd *cobra.Command, args []string) {
 		}
 	}
 
+	// values that are set by path count as flags
+	flagCount += len(setValues)
+
 	// if a config file has not been set and neither have any flags, throw an error with a help message
 	if cfgFile == "" && flagCount == 0 {
 		App.Log("SCAFF001", "fatal")
 		App.Logger.Exit(5)
 	}
 
+	// add any variables from the command line to the global variables
+	variables, _ := ccmd.Flags().GetStringArray("var")
+	vars, err := util.ParseKeyValues(variables)
+	if err != nil {
+		App.Log("GEN001", "fatal", "scaffold", err.Error())
+		return
+	}
+	Config.Input.SetVariables(vars)
+
+	// set any values that have been specified by their path in the configuration
+	_, err = setConfigValues()
+	if err != nil {
+		App.Log("GEN001", "fatal", "scaffold", err.Error())
+		return
+	}
+
+	// add any properties from the command line to the framework properties of the projects
+	properties, _ := ccmd.Flags().GetStringArray("property")
+	props, err := util.ParseKeyValues(properties)
+	if err != nil {
+		App.Log("GEN001", "fatal", "scaffold", err.Error())
+		return
+	}
+	Config.Input.SetProperties(props)
+
 	// Call the scaffolding method
 	scaff := scaffold.New(&Config, App.Logger)
-	err := scaff.Run()
+	err = scaff.Run()
 	if err != nil {
 		App.Log("GEN001", "fatal", "scaffold", err.Error())
 	}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaCmd = &cobra.Command{
+		Use:   "schema [config|settings]",
+		Short: "Output the JSON Schema for the configuration files",
+		Long: `Outputs the JSON Schema for the CLI configuration file, stacks.yml, or the project
+		settings file, stackscli.yml. The schema can be used by editors to validate and complete
+		the files as they are written`,
+		Args:        cobra.MaximumNArgs(1),
+		ValidArgs:   config.GetSchemaNames(),
+		Run:         executeSchema,
+		Annotations: map[string]string{offlineAnnotation: "true"},
+	}
+)
+
+func init() {
+
+	// declare variables that will be used to hold information from the supplied
+	// arguments
+	var output string
+
+	rootCmd.AddCommand(schemaCmd)
+
+	// Configure the flags
+	schemaCmd.Flags().StringVarP(&output, "output", "o", "", "Path to the file to write the schema to, if not set the schema is written to the screen")
+}
+
+func executeSchema(ccmd *cobra.Command, args []string) {
+
+	name := config.SchemaConfig
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	schema, ok := config.NewSchema(name)
+	if !ok {
+		App.Log("GEN001", "fatal", "schema", fmt.Sprintf("schema '%s' is not known, available schemas are %s", name, strings.Join(config.GetSchemaNames(), ", ")))
+	}
+
+	data, err := schema.JSON()
+	if err != nil {
+		App.Log("GEN001", "fatal", "schema", err.Error())
+	}
+
+	output, _ := ccmd.Flags().GetString("output")
+	if output == "" {
+		fmt.Println(string(data))
+		return
+	}
+
+	output, err = filepath.Abs(output)
+	if err == nil {
+		err = util.WriteFile(Config.GetFilesystem(), output, append(data, '\n'), 0o644)
+	}
+	if err != nil {
+		App.Log("GEN001", "fatal", "schema", err.Error())
+	}
+
+	App.Logger.Infof("Schema written to file: %s", output)
+}
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/constants"
 	"github.com/Ensono/stacks-cli/pkg/setup"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -27,10 +32,61 @@ var (
 		Use:   "list",
 		Short: "List locations where configuration files would be read from",
 		Long: `Stacks CLI allows several locations to be set for configuration file, this command
-		shows where those files would be read from`,
+		shows where those files would be read from, whether they exist and their contents`,
 		Run: executeSetupList,
 	}
 
+	setupGetCmd = &cobra.Command{
+		Use:   "get <path>",
+		Short: "Get a setting from the configuration file",
+		Long: `Show the value of a setting, in dotted notation, from the configuration file in the
+		current directory or, if --global has been set, the home directory`,
+		Args: cobra.ExactArgs(1),
+		Run:  executeSetupGet,
+	}
+
+	setupUnsetCmd = &cobra.Command{
+		Use:   "unset <path>",
+		Short: "Remove a setting from the configuration file",
+		Long: `Remove a setting, in dotted notation, from the configuration file in the
+		current directory or, if --global has been set, the home directory`,
+		Args: cobra.ExactArgs(1),
+		Run:  executeSetupUnset,
+	}
+
+	setupProfileCmd = &cobra.Command{
+		Use:   "profile",
+		Short: "Manage the profiles in the global configuration file",
+		Long: `Profiles are named sets of configuration in the global configuration file, which
+		are selected with --profile or the <prefix>_PROFILE environment variable, e.g. ENSONOSTACKS_PROFILE. The values in a profile
+		override the global values, but not the values in the directory configuration files`,
+	}
+
+	setupProfileListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles in the global configuration file",
+		Long:  `List the profiles in the global configuration file, the profile that is in use is marked with an asterisk`,
+		Args:  cobra.NoArgs,
+		Run:   executeSetupProfileList,
+	}
+
+	setupProfileUseCmd = &cobra.Command{
+		Use:   "use <name>",
+		Short: "Set the profile that is used by default",
+		Long:  `Set the profile that is used when one has not been specified with --profile or the <prefix>_PROFILE environment variable, e.g. ENSONOSTACKS_PROFILE`,
+		Args:  cobra.ExactArgs(1),
+		Run:   executeSetupProfileUse,
+	}
+
+	setupProfileCreateCmd = &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create, or update, a profile in the global configuration file",
+		Long: `Create a profile in the global configuration file, or update an existing one, with the
+		values that have been set using flags, such as --company, or --set`,
+		Args: cobra.ExactArgs(1),
+		Run:  executeSetupProfileCreate,
+	}
+
 	setupLatestCmd = &cobra.Command{
 		Use:   "latest",
 		Short: "Get the latest version of the internal configuration file",
@@ -41,6 +97,19 @@ var (
 	}
 )
 
+// profileExcludedKeys are the settings that are not added to a profile when they are set
+// using flags, as they state how the CLI runs rather than the values for a client
+var profileExcludedKeys = []string{
+	"input.directory",
+	"input.folders",
+	"input.global",
+	"input.log",
+	"input.options.dryrun",
+	"input.options.nobanner",
+	"input.options.nocliversion",
+	"input.options.onlinehelp",
+}
+
 func init() {
 
 	// declare variable that will be populated from the command line
@@ -50,6 +119,13 @@ func init() {
 	// add the sub commands for the setup command
 	setupCmd.AddCommand(setupUpdateCmd)
 	setupCmd.AddCommand(setupListCmd)
+	setupCmd.AddCommand(setupGetCmd)
+	setupCmd.AddCommand(setupUnsetCmd)
+	setupCmd.AddCommand(setupProfileCmd)
+
+	setupProfileCmd.AddCommand(setupProfileListCmd)
+	setupProfileCmd.AddCommand(setupProfileUseCmd)
+	setupProfileCmd.AddCommand(setupProfileCreateCmd)
 	setupCmd.AddCommand(setupLatestCmd)
 
 	// Add the run to the command root
@@ -58,18 +134,29 @@ func init() {
 	// Configure the flags
 	// -- Update command
 	setupUpdateCmd.Flags().StringVar(&project_name, "project", "", "The name of the project")
-	viper.BindPFlag("input.business.project", setupUpdateCmd.Flags().Lookup("project"))
+	bindFlag("input.business.project", setupUpdateCmd.Flags().Lookup("project"))
+	setupUpdateCmd.Flags().StringArrayVar(&setValues, "set", []string{}, "Set a value in the configuration file, in the form path=value, e.g. input.project[0].cloud.region=westeurope. Can be specified multiple times")
+
+	// -- Profile create command
+	setupProfileCreateCmd.Flags().StringArrayVar(&setValues, "set", []string{}, "Set a value in the profile, in the form path=value, e.g. input.terraform.backend.group=tfstate. Can be specified multiple times")
 
 	// -- Latest command
 	setupLatestCmd.Flags().StringVar(&latest_url, "url", "http://support.stacks.ensono.com/cli/config.yml", "The URL to get the latest configuration file from")
-	viper.BindPFlag("input.overrides.internal_config_url", setupLatestCmd.Flags().Lookup("url"))
+	bindFlag("input.overrides.internal_config_url", setupLatestCmd.Flags().Lookup("url"))
 }
 
 func executeSetupUpdate(ccmd *cobra.Command, args []string) {
 
+	// set any values that have been specified by their path, so that they are written to the file
+	paths, err := setConfigValues()
+	if err != nil {
+		App.Log("GEN001", "fatal", "update", err.Error())
+		return
+	}
+
 	// call the setup method
 	setup := setup.New(&Config, App.Logger)
-	err := setup.Upsert()
+	err = setup.Upsert(paths...)
 	if err != nil {
 		App.Log("GEN001", "fatal", "update", err.Error())
 	}
@@ -85,6 +172,75 @@ func executeSetupList(ccmd *cobra.Command, args []string) {
 	}
 }
 
+func executeSetupGet(ccmd *cobra.Command, args []string) {
+
+	// call the setup method
+	setup := setup.New(&Config, App.Logger)
+	err := setup.Get(args[0])
+	if err != nil {
+		App.Log("GEN001", "fatal", "get", err.Error())
+	}
+}
+
+func executeSetupUnset(ccmd *cobra.Command, args []string) {
+
+	// call the setup method
+	setup := setup.New(&Config, App.Logger)
+	err := setup.Unset(args[0])
+	if err != nil {
+		App.Log("GEN001", "fatal", "unset", err.Error())
+	}
+}
+
+func executeSetupProfileList(ccmd *cobra.Command, args []string) {
+
+	// call the setup method
+	setup := setup.New(&Config, App.Logger)
+	setup.Profile = viper.GetString(constants.ProfileKey)
+	err := setup.ProfileList()
+	if err != nil {
+		App.Log("GEN001", "fatal", "profile list", err.Error())
+	}
+}
+
+func executeSetupProfileUse(ccmd *cobra.Command, args []string) {
+
+	// call the setup method
+	setup := setup.New(&Config, App.Logger)
+	err := setup.ProfileUse(args[0])
+	if err != nil {
+		App.Log("GEN001", "fatal", "profile use", err.Error())
+	}
+}
+
+func executeSetupProfileCreate(ccmd *cobra.Command, args []string) {
+
+	// set any values that have been specified by their path
+	paths, err := setConfigValues()
+	if err != nil {
+		App.Log("GEN001", "fatal", "profile create", err.Error())
+		return
+	}
+
+	// add the values that have been set using flags, apart from the ones that only affect
+	// how the CLI runs
+	for key, flag := range flagBindings {
+		if flag.Changed && strings.HasPrefix(key, "input.") && !slices.ContainsFunc(profileExcludedKeys, func(excluded string) bool {
+			return key == excluded || strings.HasPrefix(key, excluded+".")
+		}) {
+			paths = append(paths, key)
+		}
+	}
+	sort.Strings(paths)
+
+	// call the setup method
+	setup := setup.New(&Config, App.Logger)
+	err = setup.ProfileCreate(args[0], paths...)
+	if err != nil {
+		App.Log("GEN001", "fatal", "profile create", err.Error())
+	}
+}
+
 func executeSetupLatest(ccmd *cobra.Command, args []string) {
 
 	// call the setup method
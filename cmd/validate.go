@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration and project settings files",
+		Long: `Checks the CLI configuration file, specified with -c, and a project settings file,
+		specified with --settings, against their schemas. Unknown keys, values of the wrong type,
+		unsupported pipelines and invalid regular expressions are reported with the file and line
+		on which they were found`,
+		Run:         executeValidate,
+		Annotations: map[string]string{offlineAnnotation: "true"},
+	}
+)
+
+func init() {
+
+	// declare variables that will be used to hold information from the supplied
+	// arguments
+	var settingsFile string
+
+	rootCmd.AddCommand(validateCmd)
+
+	// Configure the flags
+	validateCmd.Flags().StringVar(&settingsFile, "settings", "", "Path to the project settings file to validate, e.g. stackscli.yml")
+}
+
+func executeValidate(ccmd *cobra.Command, args []string) {
+
+	settingsFile, _ := ccmd.Flags().GetString("settings")
+
+	// build up the list of files to validate and the schema to use for each
+	files := map[string]string{}
+	if cfgFile != "" {
+		files[cfgFile] = config.SchemaConfig
+	}
+	if settingsFile != "" {
+		files[settingsFile] = config.SchemaSettings
+	}
+
+	if len(files) == 0 {
+		App.Log("VAL001", "fatal")
+	}
+
+	var count int
+	for _, path := range []string{cfgFile, settingsFile} {
+
+		name, ok := files[path]
+		if !ok {
+			continue
+		}
+
+		schema, _ := config.NewSchema(name)
+		errs, err := config.ValidateFile(path, schema)
+		if err != nil {
+			App.Log("GEN001", "fatal", "validate", err.Error())
+		}
+
+		for _, e := range errs {
+			fmt.Println(e.Error())
+		}
+
+		if len(errs) == 0 {
+			App.Logger.Infof("No problems found in file: %s", path)
+		}
+
+		count += len(errs)
+	}
+
+	if count > 0 {
+		App.Log("VAL002", "fatal", count)
+	}
+}
@@ -23,6 +23,10 @@ const (
 	// a project that is to be used with stacks
 	SettingsFile = "stackscli.yml"
 
+	// IgnoreFile is the name of the file in the root of a template that lists, using the
+	// gitignore syntax, the files that should not be copied into a project
+	IgnoreFile = ".stacksignore"
+
 	// GitHubRef is the org/name of the stacks-cli
 	// This is used as on github api calls
 	GitHubRef = "ensono/stacks-cli"
@@ -34,4 +38,10 @@ const (
 	// ConfigName is the name of the configuration file for the CLI itself
 	// This is used when traversing the filesystem to find the configuration files
 	ConfigName = "config"
+
+	// ProfileKey is the setting in the global configuration file that states the profile to use
+	ProfileKey = "profile"
+
+	// ProfilesKey is the key in the global configuration file under which profiles are set
+	ProfilesKey = "profiles"
 )
@@ -0,0 +1,359 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultTimeout is the time allowed to connect to a server and to receive the
+	// headers of the response
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultRetries is the number of times a failed request is retried
+	DefaultRetries = 3
+
+	// DefaultRetryWait is the initial time to wait before retrying a request, it is
+	// doubled for each subsequent retry
+	DefaultRetryWait = time.Second
+
+	// DefaultMaxWait is the longest time that will be waited before retrying a request,
+	// this includes waiting for a rate limit to be reset
+	DefaultMaxWait = time.Minute
+)
+
+// Options holds the settings for the HTTP client
+type Options struct {
+	Timeout   time.Duration
+	Retries   int
+	RetryWait time.Duration
+	MaxWait   time.Duration
+	CABundle  string
+	Version   string
+	Logger    *logrus.Logger
+
+	// MaxDownloadSize is the largest file, in bytes, that will be downloaded
+	MaxDownloadSize int64
+
+	// Progress states if the progress of downloads should be shown on the terminal
+	Progress bool
+}
+
+// Client is the HTTP client that is used for all network requests made by the CLI. It
+// retries requests that fail with a network error or a server error, honours Retry-After
+// headers and handles the rate limiting of the GitHub API
+type Client struct {
+	options Options
+	client  *http.Client
+
+	// sleep waits for the specified duration, it is a field so that it can be
+	// overridden in tests
+	sleep func(time.Duration)
+}
+
+var (
+	current   *Client
+	currentMu sync.RWMutex
+)
+
+// New creates an HTTP client with the specified options. The proxy is taken from the
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables and the certificates in
+// the CA bundle, if specified, are trusted in addition to the system certificates
+func New(options Options) (*Client, error) {
+
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.Retries < 0 {
+		options.Retries = 0
+	}
+	if options.RetryWait <= 0 {
+		options.RetryWait = DefaultRetryWait
+	}
+	if options.MaxWait <= 0 {
+		options.MaxWait = DefaultMaxWait
+	}
+
+	tlsConfig := &tls.Config{}
+	if options.CABundle != "" {
+		pool, err := loadCABundle(options.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// the timeout is not applied to the whole request, as downloads of large packages
+	// can take longer than the time allowed to connect
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   options.Timeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   options.Timeout,
+		ResponseHeaderTimeout: options.Timeout,
+		ExpectContinueTimeout: time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          10,
+	}
+
+	client := &Client{
+		options: options,
+		client:  &http.Client{Transport: transport},
+		sleep:   time.Sleep,
+	}
+
+	return client, nil
+}
+
+// Configure replaces the shared client with one created using the specified options
+func Configure(options Options) error {
+
+	client, err := New(options)
+	if err != nil {
+		return err
+	}
+
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	current = client
+
+	return nil
+}
+
+// Default returns the shared client. If it has not been configured, a client with the
+// default options is returned
+func Default() *Client {
+
+	currentMu.RLock()
+	client := current
+	currentMu.RUnlock()
+
+	if client != nil {
+		return client
+	}
+
+	currentMu.Lock()
+	defer currentMu.Unlock()
+
+	if current == nil {
+		current, _ = New(Options{Retries: DefaultRetries})
+	}
+
+	return current
+}
+
+// WithCheckRedirect returns a copy of the client that uses the specified function to
+// determine how redirects are followed
+func (c *Client) WithCheckRedirect(fn func(req *http.Request, via []*http.Request) error) *Client {
+
+	client := *c.client
+	client.CheckRedirect = fn
+
+	clone := *c
+	clone.client = &client
+
+	return &clone
+}
+
+// UserAgent returns the user agent that is sent with each request
+func (c *Client) UserAgent() string {
+
+	version := c.options.Version
+	if version == "" {
+		version = "dev"
+	}
+
+	return fmt.Sprintf("stacks-cli/%s", version)
+}
+
+// Get performs a GET request against the url. If the token is not empty, it is
+// added as the authorization header
+func (c *Client) Get(url string, token string) (*http.Response, error) {
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create HTTP request for '%s': %s", url, err.Error())
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+	}
+
+	return c.Do(req)
+}
+
+// Do sends the request, retrying it with an exponential backoff if it fails with a network
+// error, a server error or if the server asks for the request to be retried
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", c.UserAgent())
+	}
+
+	// requests with a body can only be retried if the body can be read again
+	retries := c.options.Retries
+	if req.Body != nil && req.GetBody == nil {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.client.Do(req)
+
+		wait, retry, rerr := c.shouldRetry(resp, err, attempt)
+		if rerr != nil {
+			drain(resp)
+			return nil, rerr
+		}
+
+		if !retry || attempt >= retries {
+			return resp, err
+		}
+
+		if err != nil {
+			c.debugf("Request to '%s' failed, retrying in %s: %s", req.URL.Redacted(), wait, err.Error())
+		} else {
+			c.debugf("Request to '%s' returned %d, retrying in %s", req.URL.Redacted(), resp.StatusCode, wait)
+		}
+
+		drain(resp)
+		c.sleep(wait)
+	}
+}
+
+// shouldRetry determines if the request should be retried and how long to wait before
+// doing so. An error is returned if the GitHub rate limit has been exceeded and it will
+// not be reset within the maximum wait time
+func (c *Client) shouldRetry(resp *http.Response, err error, attempt int) (time.Duration, bool, error) {
+
+	backoff := c.options.RetryWait << attempt
+	if backoff > c.options.MaxWait || backoff <= 0 {
+		backoff = c.options.MaxWait
+	}
+
+	if err != nil {
+		return backoff, true, nil
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0":
+
+		// the GitHub rate limit has been exceeded, wait until it resets if that is soon enough
+		reset, perr := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if perr != nil {
+			return 0, false, fmt.Errorf("GitHub API rate limit exceeded, please use a token to increase the limit")
+		}
+
+		resetAt := time.Unix(reset, 0)
+		wait := time.Until(resetAt)
+		if wait > c.options.MaxWait {
+			return 0, false, fmt.Errorf("GitHub API rate limit exceeded, the limit will be reset at %s. Please use a token to increase the limit", resetAt.Format(time.RFC1123))
+		}
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true, nil
+
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusForbidden && resp.Header.Get("Retry-After") != "":
+
+		wait, ok := retryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			wait = backoff
+		}
+		if wait > c.options.MaxWait {
+			return 0, false, fmt.Errorf("server asked for the request to be retried after %s, which is longer than the maximum wait of %s", wait, c.options.MaxWait)
+		}
+
+		return wait, true, nil
+
+	case resp.StatusCode >= 500:
+		return backoff, true, nil
+	}
+
+	return 0, false, nil
+}
+
+func (c *Client) debugf(format string, args ...interface{}) {
+	if c.options.Logger != nil {
+		c.options.Logger.Debugf(format, args...)
+	}
+}
+
+// retryAfter parses the value of a Retry-After header, which can be the number of
+// seconds to wait or an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
+
+// loadCABundle returns the system certificate pool with the certificates from the
+// PEM encoded bundle added to it
+func loadCABundle(path string) (*x509.CertPool, error) {
+
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA bundle: %s", err.Error())
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle does not contain any PEM encoded certificates: %s", path)
+	}
+
+	return pool, nil
+}
+
+// drain reads and closes the body of the response so that the connection can be reused
+func drain(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+}
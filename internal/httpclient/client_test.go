@@ -0,0 +1,195 @@
+package httpclient
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client that records the time it has been asked to wait
+// rather than sleeping
+func newTestClient(t *testing.T, options Options) (*Client, *[]time.Duration) {
+
+	client, err := New(options)
+	require.NoError(t, err)
+
+	var waits []time.Duration
+	client.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+	}
+
+	return client, &waits
+}
+
+func TestClient_UserAgent(t *testing.T) {
+
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	client, _ := newTestClient(t, Options{Version: "1.2.3"})
+
+	resp, err := client.Get(server.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "stacks-cli/1.2.3", userAgent, "User agent should contain the version of the CLI")
+}
+
+func TestClient_Retries(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		retries   int
+		status    int
+		waits     []time.Duration
+		wantErr   string
+	}{
+		{
+			name: "Server error is retried with backoff",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			retries: 3,
+			status:  http.StatusOK,
+			waits:   []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name: "Retries are limited",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusInternalServerError) },
+			},
+			retries: 1,
+			status:  http.StatusInternalServerError,
+			waits:   []time.Duration{time.Second},
+		},
+		{
+			name: "Retry-After is honoured",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "5")
+					w.WriteHeader(http.StatusTooManyRequests)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			retries: 3,
+			status:  http.StatusOK,
+			waits:   []time.Duration{5 * time.Second},
+		},
+		{
+			name: "Retry-After longer than the maximum wait",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "3600")
+					w.WriteHeader(http.StatusForbidden)
+				},
+			},
+			retries: 3,
+			wantErr: "longer than the maximum wait",
+		},
+		{
+			name: "GitHub rate limit",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+					w.WriteHeader(http.StatusForbidden)
+				},
+			},
+			retries: 3,
+			wantErr: "GitHub API rate limit exceeded",
+		},
+		{
+			name: "Client errors are not retried",
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) },
+			},
+			retries: 3,
+			status:  http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.responses[requests](w)
+				requests++
+			}))
+			defer server.Close()
+
+			client, waits := newTestClient(t, Options{Retries: tt.retries})
+
+			resp, err := client.Get(server.URL, "")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.waits, *waits, "Client should wait between retries")
+		})
+	}
+}
+
+func TestClient_CABundle(t *testing.T) {
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// without the CA bundle the certificate of the server is not trusted
+	client, _ := newTestClient(t, Options{})
+	_, err := client.Get(server.URL, "")
+	assert.Error(t, err, "Server certificate should not be trusted")
+
+	// write out the certificate of the server as the CA bundle
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, certificate, 0644))
+
+	client, _ = newTestClient(t, Options{CABundle: bundle})
+	resp, err := client.Get(server.URL, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// a bundle without any certificates is an error
+	invalid := filepath.Join(t.TempDir(), "invalid.pem")
+	require.NoError(t, os.WriteFile(invalid, []byte("not a certificate"), 0644))
+
+	_, err = New(Options{CABundle: invalid})
+	assert.ErrorContains(t, err, "does not contain any PEM encoded certificates")
+}
+
+func TestRetryAfter(t *testing.T) {
+
+	wait, ok := retryAfter("10")
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, wait)
+
+	wait, ok = retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), wait, "A date in the past should not wait")
+
+	_, ok = retryAfter("soon")
+	assert.False(t, ok)
+}
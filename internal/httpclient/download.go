@@ -0,0 +1,157 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// DefaultMaxDownloadSize is the largest file that will be downloaded, in bytes
+const DefaultMaxDownloadSize int64 = 1024 * 1024 * 1024
+
+// Download streams the file at the url to the path. If the token is not empty, it is
+// added as the authorization header
+func (c *Client) Download(url string, token string, path string) error {
+
+	resp, err := c.Get(url, token)
+	if err != nil {
+		return fmt.Errorf("unable to access requested API:\n\tURL: %s\n\t%s", url, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return fmt.Errorf("error downloading file from '%s': %d", url, resp.StatusCode)
+	}
+
+	return c.SaveResponse(resp, path)
+}
+
+// SaveResponse streams the body of the response to the file at the path. An error is
+// returned, and the file is removed, if the body is larger than the maximum download size
+func (c *Client) SaveResponse(resp *http.Response, filepath string) error {
+
+	maxSize := c.options.MaxDownloadSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxDownloadSize
+	}
+
+	if resp.ContentLength > maxSize {
+		return fmt.Errorf("file at '%s' is %d bytes, which is larger than the maximum download size of %d bytes", resp.Request.URL.Redacted(), resp.ContentLength, maxSize)
+	}
+
+	out, err := os.OpenFile(filepath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = out
+	if c.showProgress() {
+		progress := newProgress(os.Stderr, path.Base(resp.Request.URL.Path), resp.ContentLength)
+		defer progress.Done()
+
+		writer = io.MultiWriter(out, progress)
+	}
+
+	// read one byte more than the maximum so that a body that is too large can be detected
+	written, err := io.Copy(writer, io.LimitReader(resp.Body, maxSize+1))
+	out.Close()
+
+	if err == nil && written > maxSize {
+		err = fmt.Errorf("file at '%s' is larger than the maximum download size of %d bytes", resp.Request.URL.Redacted(), maxSize)
+	}
+
+	if err != nil {
+		_ = os.Remove(filepath)
+		return err
+	}
+
+	return nil
+}
+
+// showProgress states if the progress of downloads should be displayed, which is only
+// the case if it has been enabled and stderr is a terminal
+func (c *Client) showProgress() bool {
+	return c.options.Progress && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// progress writes the number of bytes that have been downloaded to the terminal
+type progress struct {
+	out     io.Writer
+	name    string
+	total   int64
+	current int64
+	updated time.Time
+}
+
+func newProgress(out io.Writer, name string, total int64) *progress {
+	return &progress{
+		out:   out,
+		name:  name,
+		total: total,
+	}
+}
+
+func (p *progress) Write(b []byte) (int, error) {
+	p.current += int64(len(b))
+
+	// limit how often the terminal is updated
+	if time.Since(p.updated) >= 100*time.Millisecond {
+		p.render()
+	}
+
+	return len(b), nil
+}
+
+// Done writes out the final progress and moves to a new line
+func (p *progress) Done() {
+	p.render()
+	fmt.Fprintln(p.out)
+}
+
+func (p *progress) render() {
+	p.updated = time.Now()
+
+	if p.total <= 0 {
+		fmt.Fprintf(p.out, "\rDownloading %s: %s", p.name, formatBytes(p.current))
+		return
+	}
+
+	width := 30
+	filled := int(float64(width) * float64(p.current) / float64(p.total))
+	if filled > width {
+		filled = width
+	}
+
+	bar := make([]byte, width)
+	for i := range bar {
+		if i < filled {
+			bar[i] = '='
+		} else {
+			bar[i] = ' '
+		}
+	}
+
+	fmt.Fprintf(p.out, "\rDownloading %s: [%s] %s / %s", p.name, bar, formatBytes(p.current), formatBytes(p.total))
+}
+
+// formatBytes returns the number of bytes in a human readable form
+func formatBytes(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
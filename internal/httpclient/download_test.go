@@ -0,0 +1,92 @@
+package httpclient
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Download(t *testing.T) {
+
+	content := bytes.Repeat([]byte("stacks"), 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/chunked":
+			// flushing before writing the content means that the length is not sent
+			w.(http.Flusher).Flush()
+			w.Write(content)
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write(content)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		maxSize int64
+		wantErr string
+	}{
+		{"Download", "/template.zip", 0, ""},
+		{"Larger than maximum size", "/template.zip", 100, "larger than the maximum download size"},
+		{"Larger than maximum size without length", "/chunked", 100, "larger than the maximum download size"},
+		{"Not found", "/missing", 0, "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, Options{MaxDownloadSize: tt.maxSize})
+
+			path := filepath.Join(t.TempDir(), "template.zip")
+			err := client.Download(server.URL+tt.path, "", path)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.NoFileExists(t, path, "Incomplete download should be removed")
+				return
+			}
+
+			require.NoError(t, err)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+
+	var out bytes.Buffer
+
+	progress := newProgress(&out, "template.zip", 2048)
+	progress.Write(make([]byte, 1024))
+	progress.Done()
+
+	assert.Contains(t, out.String(), "Downloading template.zip: [===============               ] 1.0 KiB / 2.0 KiB")
+}
+
+func TestFormatBytes(t *testing.T) {
+
+	tables := []struct {
+		size int64
+		test string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5.0 MiB"},
+	}
+
+	for _, table := range tables {
+		assert.Equal(t, table.test, formatBytes(table.size))
+	}
+}
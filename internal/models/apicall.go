@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
+
+	"github.com/Ensono/stacks-cli/internal/httpclient"
 )
 
 type APICall struct {
@@ -26,14 +27,12 @@ func NewAPICall(url string, token string) *APICall {
 func (ac *APICall) Do(method string) (error, int) {
 	var err error
 
-	// create a client to make the http request
+	// use the shared client to make the http request
 	// this so the headers can be added if required
-	client := http.Client{
-		CheckRedirect: func(r *http.Request, via []*http.Request) error {
-			r.URL.Opaque = r.URL.Path
-			return nil
-		},
-	}
+	client := httpclient.Default().WithCheckRedirect(func(r *http.Request, via []*http.Request) error {
+		r.URL.Opaque = r.URL.Path
+		return nil
+	})
 
 	req, err := http.NewRequest(method, ac.url, nil)
 	if err != nil {
@@ -42,9 +41,7 @@ func (ac *APICall) Do(method string) (error, int) {
 
 	// if the token is not null, add the headers
 	if ac.token != "" {
-		req.Header = http.Header{
-			"Authorization": []string{fmt.Sprintf("token %s", ac.token)},
-		}
+		req.Header.Set("Authorization", fmt.Sprintf("token %s", ac.token))
 	}
 
 	resp, err := client.Do(req)
@@ -55,16 +52,13 @@ func (ac *APICall) Do(method string) (error, int) {
 
 	// read all of the data returned in the call
 	// and save into a file if it has been specified
+	// only successful responses are saved to the file
 	if ac.downloadPath != "" {
-		file, err := os.Create(ac.downloadPath)
-		if err != nil {
-			return err, 0
-		}
-		defer file.Close()
-
-		_, err = io.Copy(file, resp.Body)
-		if err != nil {
-			return err, 0
+		if resp.StatusCode < 300 {
+			err = client.SaveResponse(resp, ac.downloadPath)
+			if err != nil {
+				return err, 0
+			}
 		}
 
 	} else {
@@ -75,14 +69,6 @@ func (ac *APICall) Do(method string) (error, int) {
 		return fmt.Errorf("unable to read the data from the API: %s", err.Error()), 0
 	}
 
-	/*
-		if resp.StatusCode == 403 {
-
-			// forbidden likely suggests that API access has been rate limited for the hour
-			err = fmt.Errorf("error from HTTP endpoint: %s", data["message"])
-		}
-	*/
-
 	return err, resp.StatusCode
 }
 
@@ -92,15 +78,21 @@ func (ac *APICall) Raw() []byte {
 
 func (ac *APICall) Download(filepath string) error {
 	var err error
+	var statusCode int
 
+	// reset the downloadPath to null once the download has completed
 	ac.downloadPath = filepath
-	err, _ = ac.Do("GET")
+	defer func() { ac.downloadPath = "" }()
+
+	err, statusCode = ac.Do("GET")
 	if err != nil {
 		return err
 	}
 
-	// restet the downloadPath to null
-	ac.downloadPath = ""
+	// check the status of the download
+	if statusCode > 299 {
+		return fmt.Errorf("error downloading file from '%s': %d", ac.url, statusCode)
+	}
 
 	return err
 }
@@ -49,6 +49,9 @@ func (app *App) ConfigureLogging(logging Log) {
 	// set the logging level
 	app.Logger.SetLevel(ll)
 
+	// ensure that secrets are never written out in log messages
+	app.Logger.AddHook(redactHook{})
+
 	// set the format of the log messages
 	switch logging.Format {
 	case "json":
@@ -127,15 +130,13 @@ func (app *App) HandleErrorWithFields(err error, errorType string, msg string, f
 
 func (app *App) Log(data string, level string, replacements ...interface{}) {
 
-	// get the message from help, if it does not exist use the data as is
-	message := app.Help.GetMessage(data)
+	// get the message from help and perform the substitutions, if it does not exist
+	// use the data as the message
+	message := app.Help.GetMessage(data, replacements...)
 	if message == "" {
-		message = data
+		message = fmt.Sprintf(data, replacements...)
 	}
 
-	// perform the subsitutions on the message
-	message = fmt.Sprintf(message, replacements...)
-
 	// output the message with the correct level
 	switch level {
 	case "info":
@@ -96,3 +96,24 @@ help:
 	}
 
 }
+
+func TestLogHelpMessage(t *testing.T) {
+
+	logger, hook := test.NewNullLogger()
+
+	app := App{
+		Logger: logger,
+	}
+	app.LoadHelp([]byte(`
+help:
+  - name: GEN001
+    value: "Error running %s: %s"
+`))
+
+	app.Log("GEN001", "error", "validate", "missing file")
+	assert.Equal(t, "Error running validate: missing file", hook.LastEntry().Message)
+
+	app.Log("%d problems found", "warn", 2)
+	assert.Equal(t, "2 problems found", hook.LastEntry().Message)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+}
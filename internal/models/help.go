@@ -16,15 +16,13 @@ func (h *Help) GetMessage(id string, replacements ...interface{}) string {
 	// declare the message variable
 	var message string
 
-	// find the message in the help slice
+	// find the message in the help slice, if it does not exist return an empty string
 	for _, item := range h.Help {
 		if item.Name == id {
-			message = item.Value
+			message = fmt.Sprintf(item.Value, replacements...)
 			break
 		}
 	}
 
-	message = fmt.Sprintf(message, replacements...)
-
 	return message
 }
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/Ensono/stacks-cli/internal/util"
+	log "github.com/sirupsen/logrus"
+)
+
+// redactHook replaces any secrets that have been resolved in the message, and the
+// fields, of each log entry before it is written out
+type redactHook struct{}
+
+func (h redactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h redactHook) Fire(entry *log.Entry) error {
+
+	entry.Message = util.Redact(entry.Message)
+
+	// the fields are copied so that the data that was passed in is not changed
+	if len(entry.Data) > 0 {
+		data := make(log.Fields, len(entry.Data))
+		for key, value := range entry.Data {
+			switch v := value.(type) {
+			case string:
+				data[key] = util.Redact(v)
+			case error:
+				data[key] = util.Redact(v.Error())
+			default:
+				data[key] = value
+			}
+		}
+		entry.Data = data
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactHook(t *testing.T) {
+
+	util.AddSecret("hook-secret-value")
+
+	var buf bytes.Buffer
+	logger := log.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&log.TextFormatter{DisableColors: true, DisableTimestamp: true})
+	logger.AddHook(redactHook{})
+
+	logger.WithFields(log.Fields{
+		"token": "hook-secret-value",
+		"error": errors.New("bad token hook-secret-value"),
+		"count": 1,
+	}).Info("using token hook-secret-value")
+
+	output := buf.String()
+
+	assert.NotContains(t, output, "hook-secret-value")
+	assert.Contains(t, output, util.RedactedValue)
+	assert.Contains(t, output, "count=1")
+}
@@ -0,0 +1,455 @@
+package util
+
+import (
+	"archive/tar"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractLimits holds the limits that are applied when an archive is extracted, to guard
+// against archives that expand to an excessive size or number of files
+type ExtractLimits struct {
+	MaxSize  int64
+	MaxFiles int
+}
+
+// DefaultExtractLimits are the limits that are used if they have not been configured
+var DefaultExtractLimits = ExtractLimits{
+	MaxSize:  4 * 1024 * 1024 * 1024,
+	MaxFiles: 100000,
+}
+
+var extractLimits = DefaultExtractLimits
+
+// SetExtractLimits sets the limits that are applied when extracting archives. A limit
+// that is not set uses the default value
+func SetExtractLimits(limits ExtractLimits) {
+
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = DefaultExtractLimits.MaxSize
+	}
+	if limits.MaxFiles <= 0 {
+		limits.MaxFiles = DefaultExtractLimits.MaxFiles
+	}
+
+	extractLimits = limits
+}
+
+// extractBudget tracks the size and number of files that have been extracted from
+// an archive against the limits
+type extractBudget struct {
+	limits ExtractLimits
+	size   int64
+	files  int
+}
+
+func newExtractBudget() *extractBudget {
+	return &extractBudget{limits: extractLimits}
+}
+
+// addFile counts a file in the archive, returning an error if there are too many
+func (b *extractBudget) addFile() error {
+	b.files++
+
+	if b.files > b.limits.MaxFiles {
+		return fmt.Errorf("archive contains more than the maximum of %d files", b.limits.MaxFiles)
+	}
+
+	return nil
+}
+
+// checkSize returns an error if the size would take the extracted data over the limit
+func (b *extractBudget) checkSize(size int64) error {
+	if size > b.limits.MaxSize-b.size {
+		return fmt.Errorf("archive exceeds the maximum uncompressed size of %d bytes", b.limits.MaxSize)
+	}
+
+	return nil
+}
+
+// ExtractArchive decompresses the archive at src into dest. The type of the archive is
+// determined from the file extension and can be a zip file or a tarball which is optionally
+// compressed with gzip or bzip2.
+// The number of leading path components specified by strip are removed from each of the
+// files in the archive, in the same way as `tar --strip-components`.
+// Returns the directory that the archive has been unpacked into
+func ExtractArchive(src string, dest string, strip int) (string, error) {
+
+	archiveType := ArchiveType(src)
+	if archiveType == "" {
+		return "", fmt.Errorf("unsupported archive type: %s", filepath.Base(src))
+	}
+
+	return ExtractArchiveType(src, dest, archiveType, strip)
+}
+
+// ExtractArchiveType decompresses the archive at src into dest in the same way as ExtractArchive,
+// but uses the specified archive type rather than the extension of the file. This is for
+// files that do not have an extension, such as blobs that have been downloaded from a registry
+func ExtractArchiveType(src string, dest string, archiveType string, strip int) (string, error) {
+
+	switch archiveType {
+	case "zip":
+		return unzip(src, dest, strip)
+	case "tar", "tar.gz", "tar.bz2":
+		return untar(src, dest, archiveType, strip)
+	}
+
+	return "", fmt.Errorf("unsupported archive type: %s", archiveType)
+}
+
+// ArchiveType returns the type of archive based on the extension of the file. The
+// returned value is one of "zip", "tar", "tar.gz" or "tar.bz2", or an empty string
+// if the extension is not recognised
+func ArchiveType(name string) string {
+
+	var result string
+
+	lower := strings.ToLower(name)
+
+	switch {
+	case strings.HasSuffix(lower, ".zip"), strings.HasSuffix(lower, ".nupkg"):
+		result = "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		result = "tar.gz"
+	case strings.HasSuffix(lower, ".tar.bz2"), strings.HasSuffix(lower, ".tbz2"), strings.HasSuffix(lower, ".tbz"):
+		result = "tar.bz2"
+	case strings.HasSuffix(lower, ".tar"):
+		result = "tar"
+	}
+
+	return result
+}
+
+// Untar will decompress a tar archive, which can be compressed using gzip or bzip2,
+// moving all the files and folders within the archive (parameter 1) to an output
+// directory (parameter 2).
+// Returns the top level directory of the archive, in the same way as Unzip
+func Untar(src, dest string) (string, error) {
+	return untar(src, dest, ArchiveType(src), 0)
+}
+
+func untar(src string, dest string, archiveType string, strip int) (string, error) {
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// determine the reader to use based on the compression of the file
+	var reader io.Reader = f
+	switch archiveType {
+	case "tar.gz":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return "", err
+		}
+		defer gz.Close()
+		reader = gz
+	case "tar.bz2":
+		reader = bzip2.NewReader(f)
+	}
+
+	tr := tar.NewReader(reader)
+	extractor := newArchiveExtractor(dest, strip)
+
+	// iterate around the files in the tarball
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = extractor.dir(header.Name, header.FileInfo().Mode())
+		case tar.TypeReg:
+			err = extractor.file(header.Name, tr, header.FileInfo().Mode())
+		case tar.TypeSymlink:
+			err = extractor.symlink(header.Name, header.Linkname)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return extractor.finish()
+}
+
+// stripComponents removes the specified number of leading path components from the
+// name of a file in an archive. If no components remain then false is returned so that
+// the entry can be skipped
+func stripComponents(name string, strip int) (string, bool) {
+
+	if strip <= 0 {
+		return name, true
+	}
+
+	parts := strings.Split(strings.Trim(filepath.ToSlash(name), "/"), "/")
+	if len(parts) <= strip {
+		return "", false
+	}
+
+	return strings.Join(parts[strip:], "/"), true
+}
+
+// archiveEntryPath returns the path in dest for the named file in the archive. An error
+// is returned if the path would be outside of the dest directory.
+// Check for ZipSlip. More Info: http://bit.ly/2MsjAWE
+func archiveEntryPath(dest string, name string) (string, error) {
+
+	filePath := filepath.Join(dest, name)
+
+	if filePath == filepath.Clean(dest) || !withinDir(dest, filePath) {
+		return "", fmt.Errorf("%s: illegal file path", filePath)
+	}
+
+	return filePath, nil
+}
+
+// archiveLink is a symlink in an archive, which is created once all of the files
+// in the archive have been extracted
+type archiveLink struct {
+	path   string
+	target string
+}
+
+// archiveExtractor writes the entries of an archive into the dest. It keeps track of the
+// top level entries in the archive so that the root directory can be determined, and
+// checks the entries against the limits for extracting archives
+type archiveExtractor struct {
+	dest   string
+	strip  int
+	budget *extractBudget
+
+	// roots holds the top level entries of the archive, and states if each is a directory
+	roots map[string]bool
+	links []archiveLink
+}
+
+func newArchiveExtractor(dest string, strip int) *archiveExtractor {
+	return &archiveExtractor{
+		dest:   dest,
+		strip:  strip,
+		budget: newExtractBudget(),
+		roots:  make(map[string]bool),
+	}
+}
+
+// entry returns the path in the dest for the named entry in the archive. False is
+// returned if the entry should be skipped
+func (e *archiveExtractor) entry(name string, isDir bool) (string, bool, error) {
+
+	name = path.Clean(filepath.ToSlash(name))
+	if name == "." || name == "/" {
+		return "", false, nil
+	}
+
+	// remove the leading components from the name, skipping the file if
+	// nothing remains
+	name, ok := stripComponents(name, e.strip)
+	if !ok {
+		return "", false, nil
+	}
+
+	// Determine the path for the current file and check for ZipSlip
+	filePath, err := archiveEntryPath(e.dest, name)
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := e.budget.addFile(); err != nil {
+		return "", false, err
+	}
+
+	parts := strings.SplitN(strings.Trim(name, "/"), "/", 2)
+	e.roots[parts[0]] = e.roots[parts[0]] || isDir || len(parts) > 1
+
+	return filePath, true, nil
+}
+
+// dir creates the named directory, ensuring that it can be written to
+func (e *archiveExtractor) dir(name string, mode os.FileMode) error {
+
+	dirPath, ok, err := e.entry(name, true)
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := os.MkdirAll(dirPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	return os.Chmod(dirPath, mode.Perm()|0700)
+}
+
+// file writes the content of the named file, using the mode from the archive. The file
+// is always readable and writable by the owner, so that the stackscli.yml file can be
+// read and so that the files can be deleted
+// The size of the content is checked against the budget as it is written, as the size in
+// the archive headers cannot be trusted
+func (e *archiveExtractor) file(name string, content io.Reader, mode os.FileMode) error {
+
+	filePath, ok, err := e.entry(name, false)
+	if err != nil || !ok {
+		return err
+	}
+
+	// Make the file
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return err
+	}
+
+	perm := mode.Perm()
+	if perm == 0 {
+		perm = 0644
+	}
+	perm |= 0600
+
+	// read the content of the current file so it can be set in the destination file
+	outFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	// copy the content of the current to the new one, reading one byte more than
+	// the remaining budget so that content which is too large can be detected
+	remaining := e.budget.limits.MaxSize - e.budget.size
+	written, err := io.Copy(outFile, io.LimitReader(content, remaining+1))
+	if err != nil {
+		return err
+	}
+
+	if err = e.budget.checkSize(written); err != nil {
+		return err
+	}
+	e.budget.size += written
+
+	return nil
+}
+
+// symlink checks that the target of the named symlink is within the dest and adds it to
+// the list of links to be created once all the files have been extracted. This is so that
+// no files are written through a symlink
+func (e *archiveExtractor) symlink(name string, target string) error {
+
+	linkPath, ok, err := e.entry(name, false)
+	if err != nil || !ok {
+		return err
+	}
+
+	target = filepath.FromSlash(target)
+	if target == "" || filepath.IsAbs(target) || strings.HasPrefix(target, string(os.PathSeparator)) {
+		return fmt.Errorf("%s: symlink target must be a relative path within the archive: %s", linkPath, target)
+	}
+
+	if !withinDir(e.dest, filepath.Join(filepath.Dir(linkPath), target)) {
+		return fmt.Errorf("%s: symlink target is outside of the archive: %s", linkPath, target)
+	}
+
+	e.links = append(e.links, archiveLink{path: linkPath, target: target})
+
+	return nil
+}
+
+// finish creates the symlinks in the archive and returns the root directory of the
+// unpacked archive
+func (e *archiveExtractor) finish() (string, error) {
+
+	if len(e.links) > 0 {
+
+		realDest, err := filepath.EvalSymlinks(e.dest)
+		if err != nil {
+			return "", err
+		}
+
+		for _, link := range e.links {
+
+			// the link must not be created through another link that has been created
+			parent := filepath.Dir(link.path)
+			if err := os.MkdirAll(parent, os.ModePerm); err != nil {
+				return "", err
+			}
+			realParent, err := filepath.EvalSymlinks(parent)
+			if err != nil {
+				return "", err
+			}
+			if !withinDir(realDest, realParent) {
+				return "", fmt.Errorf("%s: symlink is outside of the archive", link.path)
+			}
+
+			// replace anything that has already been written at the path
+			if _, err := os.Lstat(link.path); err == nil {
+				if err := os.RemoveAll(link.path); err != nil {
+					return "", err
+				}
+			}
+
+			if err := os.Symlink(link.target, link.path); err != nil {
+				return "", fmt.Errorf("unable to create symlink '%s': %s", link.path, err.Error())
+			}
+		}
+
+		// now that all the links exist, check that none of them resolve to a location
+		// outside of the dest, which is possible if a link refers to another link
+		for _, link := range e.links {
+			resolved, err := filepath.EvalSymlinks(link.path)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err == nil && withinDir(realDest, resolved) {
+				continue
+			}
+
+			_ = os.Remove(link.path)
+			return "", fmt.Errorf("%s: symlink target is outside of the archive: %s", link.path, link.target)
+		}
+	}
+
+	return e.rootDir()
+}
+
+// rootDir returns the directory that contains the unpacked archive. If leading
+// components have been stripped then this is the dest itself. Otherwise, if all of the
+// entries in the archive are in a single top level directory, that directory is returned
+func (e *archiveExtractor) rootDir() (string, error) {
+
+	if e.strip > 0 {
+		return e.dest, nil
+	}
+
+	if len(e.roots) == 0 {
+		return "", fmt.Errorf("archive is empty")
+	}
+
+	if len(e.roots) == 1 {
+		for name, isDir := range e.roots {
+			if isDir {
+				return filepath.Join(e.dest, name), nil
+			}
+		}
+	}
+
+	return e.dest, nil
+}
+
+// withinDir states if the path is the dir or is inside it
+func withinDir(dir string, path string) bool {
+	dir = filepath.Clean(dir)
+	path = filepath.Clean(path)
+
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
@@ -0,0 +1,428 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveTestFiles is the content that is added to the test archives
+var archiveTestFiles = map[string]string{
+	"template-1.0.0/stackscli.yml":       "framework:\n  name: infra\n",
+	"template-1.0.0/deploy/terraform.tf": "# terraform",
+}
+
+// createTarGz creates a gzip compressed tarball with the specified files
+func createTarGz(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	for name, content := range files {
+		err = tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		})
+		require.NoError(t, err)
+
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+}
+
+// createZip creates a zip file with the specified files
+func createZip(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+}
+
+func TestArchiveType(t *testing.T) {
+
+	tables := []struct {
+		name string
+		test string
+	}{
+		{"template.zip", "zip"},
+		{"ensono.stacks.templates.1.0.0.nupkg", "zip"},
+		{"template.tar.gz", "tar.gz"},
+		{"template.TGZ", "tar.gz"},
+		{"template.tar.bz2", "tar.bz2"},
+		{"template.tbz2", "tar.bz2"},
+		{"template.tar", "tar"},
+		{"template.rar", ""},
+	}
+
+	for _, table := range tables {
+		res := ArchiveType(table.name)
+		if res != table.test {
+			t.Errorf("Archive type of '%s' should be '%s', not '%s'", table.name, table.test, res)
+		}
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+
+	tables := []struct {
+		archive string
+		create  func(*testing.T, string, map[string]string)
+		strip   int
+		root    string
+		msg     string
+	}{
+		{
+			"template.tar.gz",
+			createTarGz,
+			0,
+			"template-1.0.0",
+			"Tarball should be unpacked into the top level directory",
+		},
+		{
+			"template.tgz",
+			createTarGz,
+			1,
+			"",
+			"Tarball should be unpacked into the destination when a component is stripped",
+		},
+		{
+			"template.zip",
+			createZip,
+			1,
+			"",
+			"Zip file should be unpacked into the destination when a component is stripped",
+		},
+	}
+
+	for _, table := range tables {
+		src := filepath.Join(t.TempDir(), table.archive)
+		dest := t.TempDir()
+		table.create(t, src, archiveTestFiles)
+
+		dir, err := ExtractArchive(src, dest, table.strip)
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(dest, table.root), dir, table.msg)
+		assert.FileExists(t, filepath.Join(dir, "stackscli.yml"), table.msg)
+		assert.FileExists(t, filepath.Join(dir, "deploy", "terraform.tf"), table.msg)
+	}
+}
+
+func TestExtractArchiveZipSlip(t *testing.T) {
+
+	files := map[string]string{
+		"../../evil.sh": "#!/bin/sh",
+	}
+
+	tables := []struct {
+		archive string
+		create  func(*testing.T, string, map[string]string)
+	}{
+		{"evil.tar.gz", createTarGz},
+		{"evil.zip", createZip},
+	}
+
+	for _, table := range tables {
+		src := filepath.Join(t.TempDir(), table.archive)
+		table.create(t, src, files)
+
+		_, err := ExtractArchive(src, t.TempDir(), 0)
+		assert.ErrorContains(t, err, "illegal file path", "Files outside the destination should be rejected: %s", table.archive)
+	}
+}
+
+func TestExtractArchiveUnsupported(t *testing.T) {
+	_, err := ExtractArchive("template.rar", t.TempDir(), 0)
+
+	assert.EqualError(t, err, "unsupported archive type: template.rar")
+}
+
+func TestExtractArchiveLimits(t *testing.T) {
+
+	// restore the limits once the test has completed
+	defer SetExtractLimits(DefaultExtractLimits)
+
+	files := map[string]string{
+		"template/stackscli.yml": strings.Repeat("a", 600),
+		"template/README.md":     strings.Repeat("b", 600),
+	}
+
+	tables := []struct {
+		name    string
+		limits  ExtractLimits
+		wantErr string
+	}{
+		{"Within limits", ExtractLimits{MaxSize: 2048, MaxFiles: 10}, ""},
+		{"Too large", ExtractLimits{MaxSize: 1000, MaxFiles: 10}, "maximum uncompressed size"},
+		{"Too many files", ExtractLimits{MaxSize: 2048, MaxFiles: 1}, "maximum of 1 files"},
+	}
+
+	for _, archive := range []string{"template.zip", "template.tar.gz"} {
+		for _, table := range tables {
+			t.Run(archive+" "+table.name, func(t *testing.T) {
+
+				src := filepath.Join(t.TempDir(), archive)
+				if ArchiveType(archive) == "zip" {
+					createZip(t, src, files)
+				} else {
+					createTarGz(t, src, files)
+				}
+
+				SetExtractLimits(table.limits)
+
+				_, err := ExtractArchive(src, t.TempDir(), 0)
+				if table.wantErr != "" {
+					assert.ErrorContains(t, err, table.wantErr)
+					return
+				}
+
+				assert.NoError(t, err)
+			})
+		}
+	}
+}
+
+// tarEntry is an entry to be written to a tarball by createTarEntries
+type tarEntry struct {
+	name     string
+	content  string
+	mode     int64
+	typeflag byte
+	linkname string
+}
+
+// createTarEntries creates an uncompressed tarball with the entries in the order specified
+func createTarEntries(t *testing.T, path string, entries []tarEntry) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	defer tw.Close()
+
+	for _, entry := range entries {
+		err = tw.WriteHeader(&tar.Header{
+			Name:     entry.name,
+			Mode:     entry.mode,
+			Size:     int64(len(entry.content)),
+			Typeflag: entry.typeflag,
+			Linkname: entry.linkname,
+		})
+		require.NoError(t, err)
+
+		_, err = tw.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+}
+
+func TestExtractArchiveModes(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("File modes are not supported on Windows")
+	}
+
+	src := filepath.Join(t.TempDir(), "template.tar")
+	createTarEntries(t, src, []tarEntry{
+		{name: "template/", mode: 0755, typeflag: tar.TypeDir},
+		{name: "template/build.sh", content: "#!/bin/sh", mode: 0755, typeflag: tar.TypeReg},
+		{name: "template/README.md", content: "# Template", mode: 0644, typeflag: tar.TypeReg},
+		{name: "template/readonly.txt", content: "readonly", mode: 0444, typeflag: tar.TypeReg},
+	})
+
+	dest := t.TempDir()
+	dir, err := ExtractArchive(src, dest, 0)
+	require.NoError(t, err)
+
+	tables := []struct {
+		name string
+		mode os.FileMode
+	}{
+		{"build.sh", 0755},
+		{"README.md", 0644},
+		{"readonly.txt", 0644},
+	}
+
+	for _, table := range tables {
+		info, err := os.Stat(filepath.Join(dir, table.name))
+		require.NoError(t, err)
+		assert.Equal(t, table.mode, info.Mode().Perm(), "Mode of '%s' should be preserved and writable by the owner", table.name)
+	}
+}
+
+func TestExtractArchiveSymlinks(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks require additional privileges on Windows")
+	}
+
+	tables := []struct {
+		name    string
+		entries []tarEntry
+		wantErr string
+	}{
+		{
+			name: "Symlink within archive",
+			entries: []tarEntry{
+				{name: "template/shared/main.tf", content: "# main", mode: 0644, typeflag: tar.TypeReg},
+				{name: "template/infra/shared", typeflag: tar.TypeSymlink, linkname: "../shared"},
+			},
+		},
+		{
+			name: "Absolute symlink",
+			entries: []tarEntry{
+				{name: "template/passwd", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+			},
+			wantErr: "must be a relative path",
+		},
+		{
+			name: "Symlink outside of archive",
+			entries: []tarEntry{
+				{name: "template/secrets", typeflag: tar.TypeSymlink, linkname: "../../secrets"},
+			},
+			wantErr: "outside of the archive",
+		},
+		{
+			name: "Symlink through another symlink",
+			entries: []tarEntry{
+				{name: "template/a/b/c", content: "", mode: 0644, typeflag: tar.TypeReg},
+				{name: "template/a/b/up", typeflag: tar.TypeSymlink, linkname: "../../.."},
+				{name: "template/escape", typeflag: tar.TypeSymlink, linkname: "a/b/up/.."},
+			},
+			wantErr: "outside of the archive",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+
+			src := filepath.Join(t.TempDir(), "template.tar")
+			createTarEntries(t, src, table.entries)
+
+			dest := t.TempDir()
+			dir, err := ExtractArchive(src, dest, 0)
+
+			if table.wantErr != "" {
+				assert.ErrorContains(t, err, table.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(filepath.Join(dir, "infra", "shared", "main.tf"))
+			require.NoError(t, err, "File should be readable through the symlink")
+			assert.Equal(t, "# main", string(content))
+		})
+	}
+}
+
+func TestExtractArchiveRootDir(t *testing.T) {
+
+	tables := []struct {
+		name    string
+		entries []tarEntry
+		strip   int
+		test    string
+	}{
+		{
+			name: "Single top level directory",
+			entries: []tarEntry{
+				{name: "pax_global_header", content: "", typeflag: tar.TypeXGlobalHeader},
+				{name: "stacks-template-abc123/README.md", content: "# Template", mode: 0644, typeflag: tar.TypeReg},
+			},
+			test: "stacks-template-abc123",
+		},
+		{
+			name: "Multiple top level entries",
+			entries: []tarEntry{
+				{name: "b/README.md", content: "# Template", mode: 0644, typeflag: tar.TypeReg},
+				{name: "a.txt", content: "a", mode: 0644, typeflag: tar.TypeReg},
+			},
+			test: "",
+		},
+		{
+			name: "Stripped components",
+			entries: []tarEntry{
+				{name: "template/README.md", content: "# Template", mode: 0644, typeflag: tar.TypeReg},
+			},
+			strip: 1,
+			test:  "",
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+
+			src := filepath.Join(t.TempDir(), "template.tar")
+			createTarEntries(t, src, table.entries)
+
+			dest := t.TempDir()
+			dir, err := ExtractArchive(src, dest, table.strip)
+			require.NoError(t, err)
+
+			assert.Equal(t, filepath.Join(dest, table.test), dir)
+		})
+	}
+}
+
+func TestUnzipSymlink(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks require additional privileges on Windows")
+	}
+
+	src := filepath.Join(t.TempDir(), "template.zip")
+	f, err := os.Create(src)
+	require.NoError(t, err)
+
+	zw := zip.NewWriter(f)
+
+	w, err := zw.Create("template/shared/main.tf")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("# main"))
+	require.NoError(t, err)
+
+	// the target of a symlink is stored as the content of the file
+	header := &zip.FileHeader{Name: "template/infra/shared"}
+	header.SetMode(os.ModeSymlink | 0777)
+	w, err = zw.CreateHeader(header)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("../shared"))
+	require.NoError(t, err)
+
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	dir, err := Unzip(src, t.TempDir())
+	require.NoError(t, err)
+
+	target, err := os.Readlink(filepath.Join(dir, "infra", "shared"))
+	require.NoError(t, err)
+	assert.Equal(t, "../shared", target)
+	assert.FileExists(t, filepath.Join(dir, "infra", "shared", "main.tf"))
+}
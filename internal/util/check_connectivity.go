@@ -1,9 +1,11 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"net"
-	"net/http"
+
+	"github.com/Ensono/stacks-cli/internal/httpclient"
 )
 
 func CheckConnectivity(target string) error {
@@ -12,19 +14,24 @@ func CheckConnectivity(target string) error {
 
 	msg := fmt.Sprintf("Cannot connect to '%s', is the machine offline?", target)
 
-	// check that the address can be resolved
-	_, err = net.LookupIP(target)
+	// check that the address can be resolved, removing the port if one has been specified
+	host := target
+	if h, _, splitErr := net.SplitHostPort(target); splitErr == nil {
+		host = h
+	}
+	_, err = net.LookupIP(host)
 	if err != nil {
-		return fmt.Errorf(msg)
+		return errors.New(msg)
 	}
 
 	// check that the address can be contacted
-	resp, err := http.Get(fmt.Sprintf("https://%s", target))
+	resp, err := httpclient.Default().Get(fmt.Sprintf("https://%s", target), "")
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 	if resp.StatusCode > 299 {
-		return fmt.Errorf(msg)
+		return errors.New(msg)
 	}
 
 	return err
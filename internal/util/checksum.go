@@ -0,0 +1,40 @@
+package util
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// FileChecksum returns the raw digest of the specified file using the named
+// algorithm. The supported algorithms are SHA256 and SHA512, the name is not case sensitive
+func FileChecksum(path string, algorithm string) ([]byte, error) {
+
+	var h hash.Hash
+
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %s", algorithm)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// stream the file through the hash so that large files are not read into memory
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileChecksum(t *testing.T) {
+
+	// create a file with known content to hash
+	path := filepath.Join(t.TempDir(), "package.nupkg")
+	err := os.WriteFile(path, []byte("stacks"), 0644)
+	if err != nil {
+		t.Fatalf("unable to create test file: %v", err)
+	}
+
+	tables := []struct {
+		algorithm string
+		test      string
+		msg       string
+	}{
+		{
+			"SHA256",
+			"b4f4e6c4e52c1f6b97f5567b04283facea35979cdf976d905d4c0184ecd93e34",
+			"SHA256 checksum should be '%s', not '%s'",
+		},
+		{
+			"sha512",
+			"aa09eea4031979b6c68c5da506bb3644961e114b066e6ee10614c657fb5abcf93825f915566857e41debb6137c633e55c39e2edae84384c14099faa36a5e0c26",
+			"SHA512 checksum should be '%s', not '%s'",
+		},
+	}
+
+	for _, table := range tables {
+		sum, err := FileChecksum(path, table.algorithm)
+		assert.Nil(t, err)
+
+		res := hex.EncodeToString(sum)
+		if res != table.test {
+			t.Errorf(table.msg, table.test, res)
+		}
+	}
+}
+
+func TestFileChecksumUnsupportedAlgorithm(t *testing.T) {
+	_, err := FileChecksum("package.nupkg", "md5")
+
+	assert.EqualError(t, err, "unsupported hash algorithm: md5")
+}
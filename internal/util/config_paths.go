@@ -0,0 +1,44 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Ensono/stacks-cli/internal/constants"
+)
+
+// GetConfigFilePath returns the path to the configuration file in the `.stackscli`
+// directory of the specified directory
+func GetConfigFilePath(dir string) string {
+	return NormalisePath(filepath.Join(dir, constants.ConfigFileDir, fmt.Sprintf("%s.yml", constants.ConfigName)), string(os.PathSeparator))
+}
+
+// GetConfigFilePaths returns the paths of the configuration files that the CLI reads, in the
+// order in which they are merged. The file in the home directory is read first, followed by
+// the files in every directory from the root of the filesystem down to the working directory,
+// so that the closest one is read last, and then the files in any additional folders
+func GetConfigFilePaths(homeDir string, workingDir string, folders []string) []string {
+
+	// get a list of the directories from the working directory up to, but not including,
+	// the root of the filesystem
+	var directories []string
+	for dir := filepath.Clean(workingDir); filepath.Dir(dir) != dir; dir = filepath.Dir(dir) {
+		directories = append(directories, dir)
+	}
+	slices.Reverse(directories)
+
+	directories = append([]string{homeDir}, directories...)
+	directories = append(directories, folders...)
+
+	var paths []string
+	for _, dir := range directories {
+		path := GetConfigFilePath(dir)
+		if !SliceContains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,30 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConfigFilePaths(t *testing.T) {
+
+	root := t.TempDir()
+
+	home := filepath.Join(root, "home")
+	working := filepath.Join(root, "projects", "app")
+	extra := filepath.Join(root, "extra")
+
+	paths := GetConfigFilePaths(home, working, []string{extra})
+
+	// the home file is first, the directories are in order from the root and the folders are last
+	assert.Equal(t, GetConfigFilePath(home), paths[0])
+	assert.Equal(t, GetConfigFilePath(filepath.Join(root, "projects")), paths[len(paths)-3])
+	assert.Equal(t, GetConfigFilePath(working), paths[len(paths)-2])
+	assert.Equal(t, GetConfigFilePath(extra), paths[len(paths)-1])
+
+	// a directory is only included once
+	paths = GetConfigFilePaths(working, working, nil)
+	assert.Equal(t, GetConfigFilePath(working), paths[0])
+	assert.NotContains(t, paths[1:], GetConfigFilePath(working))
+}
@@ -2,61 +2,53 @@ package util
 
 import (
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+
+	"github.com/Ensono/stacks-cli/internal/httpclient"
 )
 
 // GitClone uses standard network library to fetch a defined commit and avoids bloating the binary
-func GitClone(repoUrl, ref, trunk string, tmpPath string, token string) (string, error) {
+// The apiBase is the base URL of the GitHub API, if it is empty it is derived from the repoUrl
+func GitClone(repoUrl, apiBase, ref, trunk string, tmpPath string, token string) (string, error) {
 
 	// get the URL to be used to clone the repo from
-	archiveUrl, err := ArchiveUrl(repoUrl, ref, trunk, token)
+	archiveUrl, err := ArchiveUrl(repoUrl, apiBase, ref, trunk, token)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := http.Get(archiveUrl)
+	// stream the archive to a zip file, using the token so that archives from
+	// private repositories can be downloaded
+	zipPath := filepath.Join(os.TempDir(), fmt.Sprintf("stackscli-%s.zip", RandomString(7)))
+	defer os.Remove(zipPath)
+
+	resp, err := httpclient.Default().Get(archiveUrl, token)
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode > 299 {
 		return archiveUrl, fmt.Errorf("StatusCode: %d", resp.StatusCode)
 	}
 
-	zip, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	// write the contents of the HTTP Get to a zip file
-	zipPath := filepath.Join(os.TempDir(), RandomString(7))
-	if err := os.WriteFile(zipPath, zip, os.FileMode(0777)); err != nil {
+	if err := httpclient.Default().SaveResponse(resp, zipPath); err != nil {
 		return "", err
 	}
 
 	// unzip the downloaded files to the tempdir for the project
-	tempRepoDir, err := Unzip(zipPath, tmpPath)
-	if err != nil {
-		return "", err
-	}
-
-	// remove the zip file
-	_ = os.Remove(zipPath)
-
-	return tempRepoDir, nil
+	return Unzip(zipPath, tmpPath)
 }
 
 // ArchiveUrl returns the archive url for the repo at a given commit hash or branch or v release
-func ArchiveUrl(repoUrl, ref string, trunk string, token string) (string, error) {
+func ArchiveUrl(repoUrl, apiBase, ref string, trunk string, token string) (string, error) {
 
 	var zipUrl string
 	var err error
 
 	// get the apiUrl from the method
-	apiUrl := BuildGitHubAPIUrl(repoUrl, ref, trunk, false, token)
+	apiUrl := BuildGitHubAPIUrl(repoUrl, apiBase, ref, trunk, false, token)
 
 	if token == "" {
 		zipUrl = apiUrl
@@ -67,7 +59,7 @@ func ArchiveUrl(repoUrl, ref string, trunk string, token string) (string, error)
 
 	// if the zipUrl has not been found then drop back to the archive URL
 	if zipUrl == "" && err == nil {
-		apiUrl = BuildGitHubAPIUrl(repoUrl, ref, trunk, true, token)
+		apiUrl = BuildGitHubAPIUrl(repoUrl, apiBase, ref, trunk, true, token)
 		zipUrl, err = GetGitHubArchiveUrl(apiUrl, token)
 	}
 
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitCloneEnterprise(t *testing.T) {
+
+	// create the archive that is served for the repository
+	zipPath := filepath.Join(t.TempDir(), "template.zip")
+	createZip(t, zipPath, map[string]string{
+		"Ensono-template-abc123/stackscli.yml": "framework:\n  name: infra\n",
+	})
+	zip, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+
+	// create a server that behaves like a GitHub Enterprise Server instance, where the
+	// API is under /api/v3 and the archive can only be downloaded with the token
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Header.Get("Authorization") != "token "+token {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v3/repos/Ensono/template/releases/latest":
+			fmt.Fprintf(w, `{"zipball_url": "%s/api/v3/repos/Ensono/template/zipball/v1.0.0"}`, server.URL)
+		case "/api/v3/repos/Ensono/template/zipball/v1.0.0":
+			w.Write(zip)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+
+	dir, err := GitClone(server.URL+"/Ensono/template", server.URL+"/api/v3", "", "main", tempDir, token)
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(tempDir, "Ensono-template-abc123"), dir)
+	assert.FileExists(t, filepath.Join(dir, "stackscli.yml"))
+}
@@ -125,8 +125,16 @@ func CopySymLink(source, dest string) error {
 
 // Unzip will decompress a zip archive, moving all files and folders
 // within the zip file (parameter 1) to an output directory (parameter 2).
-// Returns all unzipped files (abs path)
+// The modes of the files and any symlinks in the archive are preserved.
+// Returns the top level directory of the archive, if all of the files are in one,
+// otherwise the output directory
 func Unzip(src, dest string) (string, error) {
+	return unzip(src, dest, 0)
+}
+
+// unzip decompresses the zip archive into the dest, removing the specified number of
+// leading path components from each file in the archive
+func unzip(src string, dest string, strip int) (string, error) {
 
 	r, err := zip.OpenReader(src)
 	if err != nil {
@@ -134,68 +142,63 @@ func Unzip(src, dest string) (string, error) {
 	}
 	defer r.Close()
 
+	extractor := newArchiveExtractor(dest, strip)
+
 	// iterate around the files in the zip
 	for _, file := range r.File {
 
-		// Determine the path for the current file
-		filePath := filepath.Join(dest, file.Name)
-
-		// Check for ZipSlip. More Info: http://bit.ly/2MsjAWE
-		if !strings.HasPrefix(filePath, filepath.Clean(dest)+string(os.PathSeparator)) {
-			return "", fmt.Errorf("%s: illegal file path", filePath)
+		// check the size that has been declared for the file, this is checked again
+		// as the file is written as it may not be correct
+		if err := extractor.budget.checkSize(int64(file.UncompressedSize64)); err != nil {
+			return "", err
 		}
 
-		// if the file is a directory, create it
-		if file.FileInfo().IsDir() {
-			os.MkdirAll(filePath, os.ModePerm)
-
-			// move onto the next iteration in the loop
-			continue
-		}
+		mode := file.Mode()
 
-		// Make the file
-		if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
-			return "", err
+		switch {
+		case mode.IsDir():
+			err = extractor.dir(file.Name, mode)
+		case mode&os.ModeSymlink != 0:
+			err = extractZipSymlink(extractor, file)
+		default:
+			err = extractZipFile(extractor, file)
 		}
-
-		// read the content of the current file so it can be set in the destination file
-		outFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
 		if err != nil {
 			return "", err
 		}
+	}
 
-		// open the current file
-		rc, err := file.Open()
-		if err != nil {
-			return "", err
-		}
+	return extractor.finish()
+}
 
-		// copy the content of the current to the new one
-		_, err = io.Copy(outFile, rc)
-		if err != nil {
-			return "", err
-		}
+// extractZipFile writes the file from the zip archive
+func extractZipFile(extractor *archiveExtractor, file *zip.File) error {
 
-		// Ensure that the file is read/writable
-		// This is so that the stackscli.yml file can be read, and so that the files can be deleted
-		if GetPlatformOS() != "windows" {
-			err = os.Chmod(filePath, 0775)
-			if err != nil {
-				return "", err
-			}
-		}
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return extractor.file(file.Name, rc, file.Mode())
+}
 
-		// close the files
-		outFile.Close()
-		rc.Close()
+// extractZipSymlink adds the symlink in the zip archive, the target of the link is
+// the content of the file
+func extractZipSymlink(extractor *archiveExtractor, file *zip.File) error {
 
+	rc, err := file.Open()
+	if err != nil {
+		return err
 	}
+	defer rc.Close()
 
-	// get the directory in the dest which is the cloned dir
-	files, _ := os.ReadDir(dest)
-	tmpRepoDir := filepath.Join(dest, files[0].Name())
+	target, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return err
+	}
 
-	return tmpRepoDir, nil
+	return extractor.symlink(file.Name, string(target))
 }
 
 // GetDefaultTempDir determines the path to be used for the temporary directory
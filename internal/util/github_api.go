@@ -4,35 +4,56 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
+	"net/url"
 	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/httpclient"
 )
 
-func CallHTTPAPI(url string, token string) (map[string]interface{}, error) {
+// GitHubHost is the host of the public GitHub service
+const GitHubHost = "github.com"
 
-	// create the data map to hold the information
-	var data map[string]interface{}
-	var err error
+// GitHubHostName returns the host of the GitHub service without a scheme or trailing slash.
+// If no host has been specified then the public GitHub host is returned
+func GitHubHostName(host string) string {
 
-	// create a client to make the http request
-	// this so the headers can be added if required
-	client := http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return data, fmt.Errorf("unable to create HTTP request for '%s': %s", url, err.Error())
+	host = strings.TrimPrefix(host, "https://")
+	host = strings.TrimPrefix(host, "http://")
+	host = strings.TrimSuffix(host, "/")
+
+	if host == "" || host == "www."+GitHubHost {
+		return GitHubHost
 	}
 
-	// if the token is not null, add the headers
-	if token != "" {
-		req.Header = http.Header{
-			"Authorization": []string{fmt.Sprintf("token %s", token)},
-		}
+	return host
+}
+
+// GitHubAPIBase returns the base URL of the GitHub API for the specified host. For github.com
+// this is https://api.github.com, for GitHub Enterprise Server it is https://<host>/api/v3
+func GitHubAPIBase(host string) string {
+
+	host = GitHubHostName(host)
+
+	if host == GitHubHost {
+		return "https://api.github.com"
 	}
 
-	resp, err := client.Do(req)
+	return fmt.Sprintf("https://%s/api/v3", host)
+}
+
+func CallHTTPAPI(url string, token string) (map[string]interface{}, error) {
+
+	// create the data map to hold the information
+	var data map[string]interface{}
+	var err error
+
+	// use the shared client to make the http request, the token is added to the
+	// headers if it has been set
+	resp, err := httpclient.Default().Get(url, token)
 	if err != nil {
 		return data, fmt.Errorf("unable to access requested API:\n\tURL: %s\n\t%s", url, err.Error())
 	}
+	defer resp.Body.Close()
 
 	// read all of the data returned in the call
 	body, _ := io.ReadAll(resp.Body)
@@ -69,17 +90,30 @@ func GetGitHubArchiveUrl(path string, token string) (string, error) {
 	return result, err
 }
 
-func BuildGitHubAPIUrl(repoUrl string, ref string, trunk string, archive bool, token string) string {
+// BuildGitHubAPIUrl returns the URL to retrieve the repository from. If the apiBase is empty
+// it is derived from the host of the repository URL
+func BuildGitHubAPIUrl(repoUrl string, apiBase string, ref string, trunk string, archive bool, token string) string {
 
-	var url string
+	var apiUrl string
 
 	// if the token is empty, then force the use of archive
 	if token == "" {
 		archive = true
 	}
 
-	// get the owner and repo name from the repoUrl
-	ownerRepoName := strings.Replace(repoUrl, "https://github.com/", "", -1)
+	// get the host, owner and repo name from the repoUrl
+	var host string
+	ownerRepoName := repoUrl
+	if u, err := url.Parse(repoUrl); err == nil && u.Host != "" {
+		host = u.Host
+		ownerRepoName = u.Path
+	}
+	ownerRepoName = strings.TrimSuffix(strings.Trim(ownerRepoName, "/"), ".git")
+
+	if apiBase == "" {
+		apiBase = GitHubAPIBase(host)
+	}
+	apiBase = strings.TrimSuffix(apiBase, "/")
 
 	// if hte ref has been set as latest or not set at all, build url to get the latest
 	// release of the repository
@@ -88,14 +122,14 @@ func BuildGitHubAPIUrl(repoUrl string, ref string, trunk string, archive bool, t
 			ref = trunk
 		}
 
-		url = strings.Join([]string{strings.TrimSuffix(repoUrl, ".git"), fmt.Sprintf("archive/%s.zip", ref)}, "/")
+		apiUrl = strings.Join([]string{strings.TrimSuffix(repoUrl, ".git"), fmt.Sprintf("archive/%s.zip", ref)}, "/")
 	} else {
 		if ref == "latest" || ref == "" {
-			url = fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", ownerRepoName)
+			apiUrl = fmt.Sprintf("%s/repos/%s/releases/latest", apiBase, ownerRepoName)
 		} else {
-			url = fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", ownerRepoName, ref)
+			apiUrl = fmt.Sprintf("%s/repos/%s/releases/tags/%s", apiBase, ownerRepoName, ref)
 		}
 	}
 
-	return url
+	return apiUrl
 }
@@ -74,10 +74,79 @@ func TestBuildGitHubAPIUrl(t *testing.T) {
 	for _, table := range tables {
 
 		// get the ghUrl from the method
-		ghUrl := BuildGitHubAPIUrl(repoUrl, table.ref, table.trunk, table.archive, table.token)
+		ghUrl := BuildGitHubAPIUrl(repoUrl, "", table.ref, table.trunk, table.archive, table.token)
 
 		if ghUrl != table.test {
 			t.Error(table.msg)
 		}
 	}
 }
+
+func TestBuildGitHubAPIUrl_Enterprise(t *testing.T) {
+
+	tables := []struct {
+		repoUrl string
+		apiBase string
+		test    string
+		msg     string
+	}{
+		{
+			"https://github.example.com/Ensono/stacks-dotnet",
+			"",
+			"https://github.example.com/api/v3/repos/Ensono/stacks-dotnet/releases/latest",
+			"The API should be derived from the host of a GitHub Enterprise Server repository",
+		},
+		{
+			"https://github.example.com/Ensono/stacks-dotnet.git",
+			"https://api.github.example.com/",
+			"https://api.github.example.com/repos/Ensono/stacks-dotnet/releases/latest",
+			"The specified API should be used",
+		},
+	}
+
+	for _, table := range tables {
+		ghUrl := BuildGitHubAPIUrl(table.repoUrl, table.apiBase, "", "main", false, token)
+
+		if ghUrl != table.test {
+			t.Errorf("%s: %s", table.msg, ghUrl)
+		}
+	}
+}
+
+func TestGitHubAPIBase(t *testing.T) {
+
+	tables := []struct {
+		host string
+		test string
+	}{
+		{"", "https://api.github.com"},
+		{"github.com", "https://api.github.com"},
+		{"github.example.com", "https://github.example.com/api/v3"},
+		{"https://github.example.com/", "https://github.example.com/api/v3"},
+	}
+
+	for _, table := range tables {
+		if actual := GitHubAPIBase(table.host); actual != table.test {
+			t.Errorf("API for host '%s' should be '%s', got '%s'", table.host, table.test, actual)
+		}
+	}
+}
+
+func TestGitHubHostName(t *testing.T) {
+
+	tables := []struct {
+		host string
+		test string
+	}{
+		{"", "github.com"},
+		{"www.github.com", "github.com"},
+		{"github.example.com", "github.example.com"},
+		{"https://github.example.com/", "github.example.com"},
+	}
+
+	for _, table := range tables {
+		if actual := GitHubHostName(table.host); actual != table.test {
+			t.Errorf("Host for '%s' should be '%s', got '%s'", table.host, table.test, actual)
+		}
+	}
+}
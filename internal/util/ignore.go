@@ -0,0 +1,202 @@
+package util
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// IgnoreMatcher determines if paths match a list of patterns that use the gitignore syntax.
+// As with gitignore, the last pattern that matches a path determines the result and a
+// pattern starting with `!` re-includes a path that has been matched by an earlier pattern
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewIgnoreMatcher creates a matcher from the lines of a gitignore style file
+func NewIgnoreMatcher(lines []string) *IgnoreMatcher {
+
+	matcher := &IgnoreMatcher{}
+
+	for _, line := range lines {
+		if pattern, ok := parseIgnorePattern(line); ok {
+			matcher.patterns = append(matcher.patterns, pattern)
+		}
+	}
+
+	return matcher
+}
+
+// ReadIgnoreFile creates a matcher from the patterns in the file. If the file does not
+// exist a matcher that does not match anything is returned
+func ReadIgnoreFile(filePath string) (*IgnoreMatcher, error) {
+
+	file, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewIgnoreMatcher(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	return NewIgnoreMatcher(lines), scanner.Err()
+}
+
+// Empty states if the matcher does not have any patterns
+func (m *IgnoreMatcher) Empty() bool {
+	return m == nil || len(m.patterns) == 0
+}
+
+// Match states if the path, which is relative to the root of the patterns and uses
+// forward slashes, is matched. A path is also matched if any of its parent
+// directories are matched
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+
+	if m.Empty() {
+		return false
+	}
+
+	relPath = strings.Trim(path.Clean(relPath), "/")
+	if relPath == "." || relPath == "" {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matchPath(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return m.matchPath(relPath, isDir)
+}
+
+// matchPath returns the result of the last pattern that matches the path
+func (m *IgnoreMatcher) matchPath(relPath string, isDir bool) bool {
+
+	matched := false
+
+	for _, pattern := range m.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+
+		if pattern.regex.MatchString(relPath) {
+			matched = !pattern.negate
+		}
+	}
+
+	return matched
+}
+
+// parseIgnorePattern converts a line from a gitignore style file into a pattern. False
+// is returned if the line is blank or a comment
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+
+	var pattern ignorePattern
+
+	// remove trailing spaces, unless they have been escaped
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = strings.TrimSuffix(line, " ")
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern, false
+	}
+
+	if strings.HasPrefix(line, "!") {
+		pattern.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		pattern.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	if line == "" {
+		return pattern, false
+	}
+
+	// a pattern that contains a slash is relative to the root, otherwise it can match
+	// at any level
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegex(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+
+	regex, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return pattern, false
+	}
+	pattern.regex = regex
+
+	return pattern, true
+}
+
+// globToRegex converts a glob, which can contain `**` to match any number of
+// directories, into a regular expression
+func globToRegex(glob string) string {
+
+	var expr strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			expr.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			expr.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return expr.String()
+}
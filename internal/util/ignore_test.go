@@ -0,0 +1,75 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreMatcher(t *testing.T) {
+
+	matcher := NewIgnoreMatcher([]string{
+		"# comment",
+		"",
+		"stackscli.yml",
+		"*.log",
+		"!keep.log",
+		"/docs",
+		"build/",
+		"test/**/fixtures",
+		".github/**",
+		"src/*.tmp",
+	})
+
+	tables := []struct {
+		path  string
+		isDir bool
+		test  bool
+	}{
+		{"stackscli.yml", false, true},
+		{"sub/stackscli.yml", false, true},
+		{"app.log", false, true},
+		{"logs/app.log", false, true},
+		{"keep.log", false, false},
+		{"docs", true, true},
+		{"docs/index.md", false, true},
+		{"src/docs", true, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"build/output.txt", false, true},
+		{"test/fixtures", true, true},
+		{"test/unit/deep/fixtures/data.json", false, true},
+		{".github/workflows/ci.yml", false, true},
+		{"src/file.tmp", false, true},
+		{"src/nested/file.tmp", false, false},
+		{"README.md", false, false},
+		{"# comment", false, false},
+	}
+
+	for _, table := range tables {
+		assert.Equal(t, table.test, matcher.Match(table.path, table.isDir), "Match of '%s' is incorrect", table.path)
+	}
+}
+
+func TestReadIgnoreFile(t *testing.T) {
+
+	dir := t.TempDir()
+
+	// a missing file does not match anything
+	matcher, err := ReadIgnoreFile(filepath.Join(dir, ".stacksignore"))
+	require.NoError(t, err)
+	assert.True(t, matcher.Empty())
+	assert.False(t, matcher.Match("README.md", false))
+
+	err = os.WriteFile(filepath.Join(dir, ".stacksignore"), []byte("docs/\n*.md\n"), 0644)
+	require.NoError(t, err)
+
+	matcher, err = ReadIgnoreFile(filepath.Join(dir, ".stacksignore"))
+	require.NoError(t, err)
+	assert.True(t, matcher.Match("README.md", false))
+	assert.True(t, matcher.Match("docs/index.html", false))
+	assert.False(t, matcher.Match("main.tf", false))
+}
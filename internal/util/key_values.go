@@ -0,0 +1,26 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseKeyValues converts a list of `key=value` strings into a map. The value can contain
+// further `=` characters, only the first one is used to split the key from the value
+func ParseKeyValues(items []string) (map[string]string, error) {
+
+	result := make(map[string]string, len(items))
+
+	for _, item := range items {
+		key, value, found := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+
+		if !found || key == "" {
+			return nil, fmt.Errorf("value must be in the form key=value: %s", item)
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
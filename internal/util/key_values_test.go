@@ -0,0 +1,27 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyValues(t *testing.T) {
+
+	values, err := ParseKeyValues([]string{"team=platform", "url=https://example.com/?a=b", "empty="})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"team":  "platform",
+		"url":   "https://example.com/?a=b",
+		"empty": "",
+	}, values)
+}
+
+func TestParseKeyValuesInvalid(t *testing.T) {
+
+	for _, item := range []string{"novalue", "=value"} {
+		_, err := ParseKeyValues([]string{item})
+		assert.Error(t, err, "'%s' should not be parsed", item)
+	}
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue is shown in place of a secret in logs and files
+const RedactedValue = "********"
+
+var (
+	secrets   []string
+	secretsMu sync.RWMutex
+)
+
+// AddSecret registers a value that must not be shown, so that it is replaced by
+// RedactedValue wherever Redact is called
+func AddSecret(value string) {
+
+	if strings.TrimSpace(value) == "" || value == RedactedValue {
+		return
+	}
+
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	if SliceContains(secrets, value) {
+		return
+	}
+
+	secrets = append(secrets, value)
+
+	// replace the longest values first, so that a secret that contains another is
+	// redacted completely
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+// Redact replaces each of the secrets that have been registered in the string
+func Redact(value string) string {
+
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	for _, secret := range secrets {
+		value = strings.ReplaceAll(value, secret, RedactedValue)
+	}
+
+	return value
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+
+	AddSecret("ghp_token")
+	AddSecret("ghp_token_longer")
+	AddSecret("   ")
+	AddSecret(RedactedValue)
+
+	tables := []struct {
+		value string
+		test  string
+	}{
+		{"git clone https://ghp_token@github.com/org/repo", "git clone https://********@github.com/org/repo"},
+		{"token=ghp_token_longer", "token=********"},
+		{"nothing to hide", "nothing to hide"},
+	}
+
+	for _, table := range tables {
+		assert.Equal(t, table.test, Redact(table.value))
+	}
+}
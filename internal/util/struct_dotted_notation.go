@@ -3,29 +3,155 @@ package util
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
+// PathSegment is one part of a dotted path, the name of a field or the key of a map and,
+// optionally, the index of an item in a list, e.g. `project[1]`
+type PathSegment struct {
+	Name  string
+	Index int
+}
+
+// HasIndex states if the segment refers to an item in a list
+func (p PathSegment) HasIndex() bool {
+	return p.Index >= 0
+}
+
+func (p PathSegment) String() string {
+	if p.HasIndex() {
+		return fmt.Sprintf("%s[%d]", p.Name, p.Index)
+	}
+
+	return p.Name
+}
+
+// ParseDottedPath splits a dotted path into its segments. Each segment can have an index
+// to refer to an item in a list, e.g. `input.project[1].cloud.region`
+func ParseDottedPath(path string) ([]PathSegment, error) {
+
+	var segments []PathSegment
+
+	for _, part := range strings.Split(path, ".") {
+
+		segment := PathSegment{Name: part, Index: -1}
+
+		if start := strings.Index(part, "["); start > -1 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid index in path '%s': %s", path, part)
+			}
+
+			index, err := strconv.Atoi(part[start+1 : len(part)-1])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid index in path '%s': %s", path, part)
+			}
+
+			segment.Name = part[:start]
+			segment.Index = index
+		}
+
+		if segment.Name == "" {
+			return nil, fmt.Errorf("invalid path: %s", path)
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// CompareDottedPaths compares two dotted paths segment by segment, so that indexes are
+// compared as numbers, e.g. `project[2]` is before `project[10]`. It returns a negative
+// number if a is before b, a positive number if it is after and zero if they are the same.
+// Paths that cannot be parsed are compared as strings
+func CompareDottedPaths(a string, b string) int {
+
+	left, errA := ParseDottedPath(a)
+	right, errB := ParseDottedPath(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	for i := 0; i < len(left) && i < len(right); i++ {
+		if c := strings.Compare(left[i].Name, right[i].Name); c != 0 {
+			return c
+		}
+		if left[i].Index != right[i].Index {
+			return left[i].Index - right[i].Index
+		}
+	}
+
+	return len(left) - len(right)
+}
+
+// GetStructField returns the field of the struct that matches the name. The name is matched
+// without case against the name of the field and the name in its mapstructure tag, so that
+// paths can use the names in the configuration file
+func GetStructField(t reflect.Type, name string) (reflect.StructField, bool) {
+
+	if field, ok := t.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, name) }); ok {
+		return field, true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if field.IsExported() && tag != "" && strings.EqualFold(tag, name) {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
 // GetValueByDottedPath returns the value of a field in a struct by using a string
 // This is useful when checking the values of a struct for a specific value, or if it is null
-// The string must be names of the attributes in the struct and not the ones in the mapstructure
+// The string can use the names of the attributes in the struct or the ones in the mapstructure
+// tags, the keys of maps and the index of items in lists, e.g. `project[0].name`
 func GetValueByDottedPath(data interface{}, path string) (interface{}, error) {
 
 	// get the name of the fields that are being sought
-	fields := strings.Split(path, ".")
+	segments, err := ParseDottedPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	val := reflect.ValueOf(data)
 
 	// iterate around the fields
-	for _, field := range fields {
-		if val.Kind() == reflect.Ptr {
-			val = reflect.ValueOf(val).Elem()
+	for _, segment := range segments {
+		for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+			val = val.Elem()
+		}
+
+		switch val.Kind() {
+		case reflect.Struct:
+			field, ok := GetStructField(val.Type(), segment.Name)
+			if !ok {
+				return nil, fmt.Errorf("unable to find field: %s", segment.Name)
+			}
+			val = val.FieldByIndex(field.Index)
+		case reflect.Map:
+			if val.Type().Key().Kind() != reflect.String {
+				return nil, fmt.Errorf("unable to find field: %s", segment.Name)
+			}
+			val = val.MapIndex(reflect.ValueOf(segment.Name).Convert(val.Type().Key()))
+			if !val.IsValid() {
+				return nil, fmt.Errorf("field not found: %s", path)
+			}
+		default:
+			return nil, fmt.Errorf("unable to find field: %s", segment.Name)
 		}
 
-		if val.Kind() == reflect.Struct {
-			// val = val.FieldByNameFunc(func(n string) bool { return strings.ToLower(n) == field })
-			val = caseInsensitiveFieldByName(val, field)
-		} else {
-			return nil, fmt.Errorf("unable to find field: %s", field)
+		if segment.HasIndex() {
+			if val.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("field is not a list: %s", segment.Name)
+			}
+			if segment.Index >= val.Len() {
+				return nil, fmt.Errorf("index %d is out of range for '%s', it has %d items", segment.Index, segment.Name, val.Len())
+			}
+			val = val.Index(segment.Index)
 		}
 	}
 
@@ -36,7 +162,177 @@ func GetValueByDottedPath(data interface{}, path string) (interface{}, error) {
 	return nil, fmt.Errorf("field not found: %s", path)
 }
 
-func caseInsensitiveFieldByName(v reflect.Value, name string) reflect.Value {
-	name = strings.ToLower(name)
-	return v.FieldByNameFunc(func(n string) bool { return strings.ToLower(n) == name })
+// SetValueByDottedPath sets the value of a field in a struct by using a string, in the same
+// form as GetValueByDottedPath. The data must be a pointer to the struct. The value is converted
+// to the type of the field, lists of values are set from a comma separated string. An item
+// can be added to the end of a list by using the next index, e.g. `project[1]` when there is
+// only one project
+func SetValueByDottedPath(data interface{}, path string, value string) error {
+
+	val := reflect.ValueOf(data)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return fmt.Errorf("unable to set '%s', the value is not a pointer", path)
+	}
+
+	segments, err := ParseDottedPath(path)
+	if err != nil {
+		return err
+	}
+
+	return setValue(val.Elem(), segments, "", value)
+}
+
+// setValue finds the field for the first segment in the value and sets the remaining
+// segments on it, parent is the path to the value and is used in error messages
+func setValue(val reflect.Value, segments []PathSegment, parent string, value string) error {
+
+	if len(segments) == 0 {
+		return convertValue(val, parent, value)
+	}
+
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		val = val.Elem()
+	}
+
+	segment := segments[0]
+	path := segment.Name
+	if parent != "" {
+		path = fmt.Sprintf("%s.%s", parent, segment.Name)
+	}
+
+	switch val.Kind() {
+	case reflect.Struct:
+		field, ok := GetStructField(val.Type(), segment.Name)
+		if !ok || !field.IsExported() {
+			if parent == "" {
+				return fmt.Errorf("unknown field '%s'", segment.Name)
+			}
+			return fmt.Errorf("unknown field '%s' in '%s'", segment.Name, parent)
+		}
+
+		return setItem(val.FieldByIndex(field.Index), segment, segments[1:], path, value)
+
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unable to set '%s', the keys of the map are not strings", path)
+		}
+
+		if val.IsNil() {
+			val.Set(reflect.MakeMap(val.Type()))
+		}
+
+		// values in a map cannot be changed in place, so a copy is changed and then stored
+		key := reflect.ValueOf(segment.Name).Convert(val.Type().Key())
+		item := reflect.New(val.Type().Elem()).Elem()
+		if existing := val.MapIndex(key); existing.IsValid() {
+			item.Set(existing)
+		}
+
+		err := setItem(item, segment, segments[1:], path, value)
+		if err != nil {
+			return err
+		}
+
+		val.SetMapIndex(key, item)
+		return nil
+	}
+
+	return fmt.Errorf("unable to set '%s', '%s' does not have any fields", path, parent)
+}
+
+// setItem sets the remaining segments on the value, or the item in the value if the
+// segment has an index
+func setItem(val reflect.Value, segment PathSegment, segments []PathSegment, path string, value string) error {
+
+	if !segment.HasIndex() {
+		return setValue(val, segments, path, value)
+	}
+
+	if val.Kind() != reflect.Slice {
+		return fmt.Errorf("unable to set '%s', it is not a list", path)
+	}
+
+	if segment.Index > val.Len() {
+		return fmt.Errorf("index %d is out of range for '%s', it has %d items", segment.Index, path, val.Len())
+	}
+
+	// add a new item to the end of the list
+	if segment.Index == val.Len() {
+		val.Set(reflect.Append(val, reflect.Zero(val.Type().Elem())))
+	}
+
+	return setValue(val.Index(segment.Index), segments, fmt.Sprintf("%s[%d]", path, segment.Index), value)
+}
+
+// convertValue converts the string to the type of the value and sets it
+func convertValue(val reflect.Value, path string, value string) error {
+
+	var err error
+
+	switch val.Kind() {
+	case reflect.String:
+		val.SetString(value)
+		return nil
+
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(value); err == nil {
+			val.SetBool(b)
+			return nil
+		}
+		return fmt.Errorf("value '%s' for '%s' is not a valid boolean", value, path)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(value, 10, val.Type().Bits()); err == nil {
+			val.SetInt(i)
+			return nil
+		}
+		return fmt.Errorf("value '%s' for '%s' is not a valid integer", value, path)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(value, 10, val.Type().Bits()); err == nil {
+			val.SetUint(u)
+			return nil
+		}
+		return fmt.Errorf("value '%s' for '%s' is not a valid integer", value, path)
+
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(value, val.Type().Bits()); err == nil {
+			val.SetFloat(f)
+			return nil
+		}
+		return fmt.Errorf("value '%s' for '%s' is not a valid number", value, path)
+
+	case reflect.Slice:
+		// lists are set from a comma separated string
+		var items []string
+		if value != "" {
+			items = strings.Split(value, ",")
+		}
+
+		list := reflect.MakeSlice(val.Type(), len(items), len(items))
+		for i, item := range items {
+			err = convertValue(list.Index(i), fmt.Sprintf("%s[%d]", path, i), strings.TrimSpace(item))
+			if err != nil {
+				return err
+			}
+		}
+
+		val.Set(list)
+		return nil
+
+	case reflect.Ptr:
+		if val.IsNil() {
+			val.Set(reflect.New(val.Type().Elem()))
+		}
+		return convertValue(val.Elem(), path, value)
+	}
+
+	return fmt.Errorf("unable to set '%s' to a value, set one of the values within it", path)
 }
@@ -1,6 +1,10 @@
 package util
 
-import "testing"
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
 
 func TestGetFieldBYDottedPath(t *testing.T) {
 
@@ -52,3 +56,139 @@ func TestGetFieldBYDottedPath(t *testing.T) {
 		}
 	}
 }
+
+type dottedItem struct {
+	Name    string
+	Enabled bool     `mapstructure:"is_enabled"`
+	Count   int      `mapstructure:"count"`
+	Tags    []string `mapstructure:"tags"`
+}
+
+type dottedConfig struct {
+	Items  []dottedItem      `mapstructure:"items"`
+	Labels map[string]string `mapstructure:"labels"`
+	Nested map[string]dottedItem
+}
+
+func TestGetValueByDottedPathIndex(t *testing.T) {
+
+	data := dottedConfig{
+		Items:  []dottedItem{{Name: "first"}, {Name: "second", Enabled: true}},
+		Labels: map[string]string{"team": "platform"},
+	}
+
+	tables := []struct {
+		path string
+		test interface{}
+	}{
+		{"items[1].name", "second"},
+		{"Items[1].is_enabled", true},
+		{"labels.team", "platform"},
+	}
+
+	for _, table := range tables {
+		value, err := GetValueByDottedPath(&data, table.path)
+		if err != nil {
+			t.Errorf("Unable to get '%s': %s", table.path, err.Error())
+		} else if value != table.test {
+			t.Errorf("Value of '%s' should be '%v' not '%v'", table.path, table.test, value)
+		}
+	}
+
+	for _, path := range []string{"items[2].name", "labels.missing", "items.name[", "unknown"} {
+		if _, err := GetValueByDottedPath(data, path); err == nil {
+			t.Errorf("Getting '%s' should return an error", path)
+		}
+	}
+}
+
+func TestSetValueByDottedPath(t *testing.T) {
+
+	data := dottedConfig{
+		Items: []dottedItem{{Name: "first"}},
+	}
+
+	tables := []struct {
+		path  string
+		value string
+	}{
+		{"items[0].is_enabled", "true"},
+		{"items[1].name", "second"},
+		{"items[1].count", "3"},
+		{"items[0].tags", "a, b"},
+		{"labels.team", "platform"},
+		{"nested.one.name", "nested"},
+	}
+
+	for _, table := range tables {
+		if err := SetValueByDottedPath(&data, table.path, table.value); err != nil {
+			t.Errorf("Unable to set '%s': %s", table.path, err.Error())
+		}
+	}
+
+	expected := dottedConfig{
+		Items: []dottedItem{
+			{Name: "first", Enabled: true, Tags: []string{"a", "b"}},
+			{Name: "second", Count: 3},
+		},
+		Labels: map[string]string{"team": "platform"},
+		Nested: map[string]dottedItem{"one": {Name: "nested"}},
+	}
+
+	if !reflect.DeepEqual(expected, data) {
+		t.Errorf("Values have not been set correctly: %+v", data)
+	}
+
+	errors := []struct {
+		path  string
+		value string
+		test  string
+	}{
+		{"items[0].unknown", "x", "unknown field 'unknown' in 'items[0]'"},
+		{"items[3].name", "x", "index 3 is out of range for 'items', it has 2 items"},
+		{"items[0].count", "many", "value 'many' for 'items[0].count' is not a valid integer"},
+		{"items[0].is_enabled", "maybe", "value 'maybe' for 'items[0].is_enabled' is not a valid boolean"},
+		{"items[0]", "x", "unable to set 'items[0]' to a value, set one of the values within it"},
+		{"nested.one.tags[1]", "x", "index 1 is out of range for 'nested.one.tags', it has 0 items"},
+	}
+
+	for _, table := range errors {
+		err := SetValueByDottedPath(&data, table.path, table.value)
+		if err == nil || err.Error() != table.test {
+			t.Errorf("Setting '%s' should return the error '%s', got: %v", table.path, table.test, err)
+		}
+	}
+
+	if err := SetValueByDottedPath(data, "labels.team", "x"); err == nil {
+		t.Error("Setting a value on a struct that is not a pointer should return an error")
+	}
+}
+
+func TestCompareDottedPaths(t *testing.T) {
+
+	paths := []string{
+		"input.project[10].name",
+		"input.project[2].name",
+		"input.project.name",
+		"input.project[2]",
+		"input.options.dryrun",
+		"input.project[1].cloud.region",
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return CompareDottedPaths(paths[i], paths[j]) < 0
+	})
+
+	expected := []string{
+		"input.options.dryrun",
+		"input.project.name",
+		"input.project[1].cloud.region",
+		"input.project[2]",
+		"input.project[2].name",
+		"input.project[10].name",
+	}
+
+	if !reflect.DeepEqual(expected, paths) {
+		t.Errorf("Paths are not in the expected order: %v", paths)
+	}
+}
@@ -231,7 +231,7 @@ func (a *Answers) getProjectQuestions(qType string, config *Config) []*survey.Qu
 				Prompt: &survey.Input{
 					Message: "Specify any additional framework properties. (Use a comma to separate each one).",
 					Default: "",
-					Help:    "Additional properties that need to applied to the project when it is built. This is dependent on the framework that has been chosen. Multiple options can be specified by separating the options with a comma",
+					Help:    "Additional properties that need to applied to the project when it is built. This is dependent on the framework that has been chosen. Multiple options can be specified by separating the options with a comma. Properties in the form key=value can be referenced individually in templates",
 				},
 			},
 		}
@@ -258,7 +258,7 @@ func (a *Answers) getProjectQuestions(qType string, config *Config) []*survey.Qu
 				Prompt: &survey.Input{
 					Message: "Specify any additional framework properties. (Use a comma to separate each one).",
 					Default: "",
-					Help:    "Additional properties that need to applied to the project when it is built. This is dependent on the framework that has been chosen. Multiple options can be specified by separating the options with a comma",
+					Help:    "Additional properties that need to applied to the project when it is built. This is dependent on the framework that has been chosen. Multiple options can be specified by separating the options with a comma. Properties in the form key=value can be referenced individually in templates",
 				},
 			},
 			{
@@ -485,14 +485,9 @@ func (a *Answers) RunInteractive(config *Config) error {
 		}
 
 		// check to see if any properties have been specified, and if they have
-		// create a properties object to work with the
-		properties := []string{}
-		if pa.FrameworkProperties != "" {
-
-			// split the properties based comma and then iterate around setting the framework properties
-			properties = strings.Split(pa.FrameworkProperties, ",")
-
-		}
+		// split them on the comma, trimming each one. Properties in the form key=value
+		// are added to the map of properties so they can be used in templates
+		properties := NewProperties(strings.Split(pa.FrameworkProperties, ","))
 
 		// create a struct for the project
 		project := Project{
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -135,6 +136,29 @@ func (c *Config) Save(usedConfig string) (string, error) {
 	return savedConfigFile, err
 }
 
+// SetValues sets each of the values in the configuration, the keys are dotted paths to the
+// value, e.g. `input.project[1].cloud.region`. The values are set in order of their paths,
+// with indexes compared as numbers, so that items are added to lists in order
+func (c *Config) SetValues(values map[string]string) error {
+
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return util.CompareDottedPaths(paths[i], paths[j]) < 0
+	})
+
+	for _, path := range paths {
+		err := util.SetValueByDottedPath(c, path, values[path])
+		if err != nil {
+			return fmt.Errorf("unable to set '%s': %s", path, err.Error())
+		}
+	}
+
+	return nil
+}
+
 // GetVersion returns the current version of the application
 // It will check to see uif the Version is empty, if it is, it will
 // set and identifiable local build version
@@ -178,7 +202,11 @@ func (config *Config) WriteVariablesFile(project *Project, pipelineSettings Pipe
 	}
 
 	// render the variable file
-	rendered, err := config.RenderTemplate(filepath.Base(variableFile), variableTemplate, replacements)
+	options := RenderOptions{
+		Delimiters: pipelineSettings.GetTemplateDelimiters("variable"),
+		Strict:     pipelineSettings.IsStrict(),
+	}
+	rendered, err := config.RenderTemplateWithOptions(filepath.Base(variableFile), variableTemplate, replacements, options)
 
 	if err != nil {
 		return fmt.Sprintf("Problem rendering variable template file: %s", err.Error()), err
@@ -194,33 +222,51 @@ func (config *Config) WriteVariablesFile(project *Project, pipelineSettings Pipe
 	return "", err
 }
 
-// renderTemplate takes any string and attempts to replace items in it based
+// RenderTemplate takes any string and attempts to replace items in it based
 // on the values in the supplied Input object
 func (config *Config) RenderTemplate(name string, tmpl string, input Replacements) (string, error) {
+	return config.RenderTemplateWithOptions(name, tmpl, input, RenderOptions{})
+}
+
+// RenderTemplateWithOptions renders the template using the specified delimiters to find
+// the actions, so that templates containing other `{{ }}` expressions can be rendered.
+// In strict mode the template fails to render if it references a value that does not exist
+// or outputs a value that is empty, the error contains the name of the template, the line
+// and the path to the value
+func (config *Config) RenderTemplateWithOptions(name string, tmpl string, input Replacements, options RenderOptions) (string, error) {
 
 	// declare var to hold the rendered string
 	var rendered bytes.Buffer
 
-	// create a function map that passes in functions to the template that
-	// can be used to run simple string operations from within the template
-	funcMap := template.FuncMap{
-		"toLower": strings.ToLower,
-		"toUpper": strings.ToUpper,
-		"trim":    strings.TrimSpace,
+	if err := options.Delimiters.Validate(); err != nil {
+		return "", err
 	}
+	left, right := options.Delimiters.Get()
+
+	funcMap := templateFuncs(left, right)
+	funcMap[requiredFunc] = required
 
 	// create an object of the template
 	// if it fails then return with an error
-	t, err := template.New(name).Funcs(funcMap).Parse(tmpl)
+	t, err := template.New(name).Delims(left, right).Funcs(funcMap).Parse(tmpl)
 
 	if err != nil {
 		return "", err
 	}
 
+	if err := checkSecretFields(t); err != nil {
+		return "", err
+	}
+
+	if options.Strict {
+		t = t.Option("missingkey=error")
+		makeStrict(t)
+	}
+
 	// render the template into the variable
 	err = t.Execute(&rendered, input)
 	if err != nil {
-		return "", err
+		return "", strictError(err)
 	}
 
 	return rendered.String(), nil
@@ -290,8 +336,8 @@ func (config *Config) WriteCmdLog(path string, cmd string) error {
 	}
 	defer f.Close()
 
-	// write out the cmd to the file
-	if _, err := f.Write([]byte(fmt.Sprintf("[%s] %s\n", path, cmd))); err != nil {
+	// write out the cmd to the file, with any secrets that it contains redacted
+	if _, err := f.Write([]byte(util.Redact(fmt.Sprintf("[%s] %s\n", path, cmd)))); err != nil {
 		return err
 	}
 
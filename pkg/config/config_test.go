@@ -101,6 +101,61 @@ func TestTemplateString(t *testing.T) {
 	assert.Equal(t, expected, rendered)
 }
 
+// TestTemplateStringWithDelimiters tests that templates can use alternative delimiters
+// and that literal delimiters can be output
+func TestTemplateStringWithDelimiters(t *testing.T) {
+
+	cfg := Config{}
+	cfg.Input.Business.Company = "my-company"
+
+	replacements := Replacements{}
+	replacements.Input = cfg.Input
+
+	tables := []struct {
+		tmpl       string
+		delimiters Delimiters
+		expected   string
+		err        bool
+	}{
+		{
+			"token: ${{ secrets.TOKEN }}; company: [[ .Input.Business.Company ]]",
+			Delimiters{Left: "[[", Right: "]]"},
+			"token: ${{ secrets.TOKEN }}; company: my-company",
+			false,
+		},
+		{
+			"<% .Input.Business.Company %> <% ldelim %> <% rdelim %>",
+			Delimiters{Left: "<%", Right: "%>"},
+			"my-company <% %>",
+			false,
+		},
+		{
+			`token: {{ raw "${{ secrets.TOKEN }}" }}; {{ ldelim }} {{ rdelim }}`,
+			Delimiters{},
+			"token: ${{ secrets.TOKEN }}; {{ }}",
+			false,
+		},
+		{
+			"[[ .Input.Business.Company ]]",
+			Delimiters{Left: "[[", Right: "[["},
+			"",
+			true,
+		},
+	}
+
+	for _, table := range tables {
+		rendered, err := cfg.RenderTemplateWithOptions("string", table.tmpl, replacements, RenderOptions{Delimiters: table.delimiters})
+
+		if table.err {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, table.expected, rendered)
+	}
+}
+
 func TestWriteVariableTemplate(t *testing.T) {
 
 	fs := memfs.New()
@@ -276,6 +331,37 @@ func TestWriteCmdLog(t *testing.T) {
 
 }
 
+func TestWriteCmdLogRedactsSecrets(t *testing.T) {
+
+	// setup the environment
+	cleanup, fs, dir := setupConfigTests(t)
+	defer cleanup(t)
+
+	config := Config{
+		Filesystem: fs,
+		Input: InputConfig{
+			Directory: Directory{
+				WorkingDir: dir,
+			},
+		},
+	}
+	config.Init()
+	config.Input.Options.CmdLog = true
+	config.SetDefaultValues()
+
+	// register the secret as it would be when the token is resolved
+	util.AddSecret("cmdlog-secret-value")
+
+	err := config.WriteCmdLog(dir, "git clone https://cmdlog-secret-value@github.com/org/repo")
+	assert.Equal(t, nil, err)
+
+	expected := fmt.Sprintf("[%s] git clone https://%s@github.com/org/repo\n", dir, util.RedactedValue)
+	actual, readErr := util.ReadFile(fs, config.Self.CmdLogPath)
+
+	assert.Equal(t, nil, readErr)
+	assert.Equal(t, expected, string(actual))
+}
+
 func TestCheck(t *testing.T) {
 
 	// create a test table to iterate around
@@ -684,3 +770,43 @@ func TestGetFrameworkCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestSetValues(t *testing.T) {
+
+	cfg := Config{
+		Input: InputConfig{
+			Project: []Project{{Name: "first"}},
+		},
+	}
+
+	err := cfg.SetValues(map[string]string{
+		"input.project[1].cloud.region": "westeurope",
+		"input.project[1].name":         "second",
+		"input.options.dryrun":          "true",
+		"input.options.timeout":         "60",
+		"input.folders":                 "one,two",
+		"input.variables.env":           "dev",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(cfg.Input.Project))
+	assert.Equal(t, "second", cfg.Input.Project[1].Name)
+	assert.Equal(t, "westeurope", cfg.Input.Project[1].Cloud.Region)
+	assert.True(t, cfg.Input.Options.DryRun)
+	assert.Equal(t, 60, cfg.Input.Options.Timeout)
+	assert.Equal(t, []string{"one", "two"}, cfg.Input.Folders)
+	assert.Equal(t, "dev", cfg.Input.Variables["env"])
+
+	// items are added to the list in the order of their index
+	values := map[string]string{}
+	for i := 2; i <= 11; i++ {
+		values[fmt.Sprintf("input.project[%d].name", i)] = fmt.Sprintf("project%d", i)
+	}
+	err = cfg.SetValues(values)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, len(cfg.Input.Project))
+	assert.Equal(t, "project10", cfg.Input.Project[10].Name)
+
+	err = cfg.SetValues(map[string]string{"input.project[0].regoin": "x"})
+	assert.EqualError(t, err, "unable to set 'input.project[0].regoin': unknown field 'regoin' in 'input.project[0]'")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DefaultLeftDelimiter is the delimiter that starts an action in a template
+	DefaultLeftDelimiter = "{{"
+
+	// DefaultRightDelimiter is the delimiter that ends an action in a template
+	DefaultRightDelimiter = "}}"
+)
+
+// Delimiters holds the delimiters that are used to find the actions in a template. Templates
+// that contain GitHub Actions, Helm or Azure Pipelines expressions, which also use `{{ }}`,
+// can use alternative delimiters such as `[[ ]]` or `<% %>`
+type Delimiters struct {
+	Left  string `mapstructure:"left" yaml:"left,omitempty"`
+	Right string `mapstructure:"right" yaml:"right,omitempty"`
+}
+
+// IsSet states if any of the delimiters have been set
+func (d Delimiters) IsSet() bool {
+	return d.Left != "" || d.Right != ""
+}
+
+// Or returns the delimiters if they have been set, otherwise the fallback is returned
+func (d Delimiters) Or(fallback Delimiters) Delimiters {
+	if d.IsSet() {
+		return d
+	}
+
+	return fallback
+}
+
+// Get returns the left and right delimiters, using the default for any that have not been set
+func (d Delimiters) Get() (string, string) {
+
+	left, right := d.Left, d.Right
+
+	if left == "" {
+		left = DefaultLeftDelimiter
+	}
+
+	if right == "" {
+		right = DefaultRightDelimiter
+	}
+
+	return left, right
+}
+
+// Validate checks that the delimiters can be used to parse a template
+func (d Delimiters) Validate() error {
+
+	left, right := d.Get()
+
+	if strings.TrimSpace(left) != left || strings.TrimSpace(right) != right {
+		return fmt.Errorf("template delimiters must not start or end with whitespace: '%s' '%s'", left, right)
+	}
+
+	if left == right {
+		return fmt.Errorf("template delimiters must be different: '%s'", left)
+	}
+
+	return nil
+}
@@ -3,11 +3,11 @@ package config
 import "fmt"
 
 type Framework struct {
-	Type           string   `mapstructure:"type"`
-	Option         string   `mapstructure:"option"`
-	Version        string   `mapstructure:"version" yaml:",omitempty"`                        // Version of the project to download
-	Properties     []string `mapstructure:"properties" yaml:",omitempty"`                     // additional properties to be specified that need to be passed to project commands
-	DeploymentMode string   `mapstructure:"deployment_mode" yaml:"deployment_mode,omitempty"` //AKS or ACA
+	Type           string     `mapstructure:"type"`
+	Option         string     `mapstructure:"option"`
+	Version        string     `mapstructure:"version" yaml:",omitempty"`                        // Version of the project to download
+	Properties     Properties `mapstructure:"properties" yaml:",omitempty"`                     // additional properties to be specified that need to be passed to project commands
+	DeploymentMode string     `mapstructure:"deployment_mode" yaml:"deployment_mode,omitempty"` //AKS or ACA
 }
 
 // GetMapKey returns the key to be used in the srcUrl map to
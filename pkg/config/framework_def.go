@@ -1,8 +1,9 @@
 package config
 
 type FrameworkDef struct {
-	Name     string            `mapstructure:"name" yaml:"name"`
-	Commands []FrameworkDefCmd `mapstructure:"commands" yaml:"commands"`
+	Name          string            `mapstructure:"name" yaml:"name"`
+	Commands      []FrameworkDefCmd `mapstructure:"commands" yaml:"commands"`
+	PropertyStyle string            `mapstructure:"property_style" yaml:"property_style,omitempty"`
 }
 
 func (fd *FrameworkDef) GetCmdList() []string {
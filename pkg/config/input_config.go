@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os/exec"
 	"regexp"
+	"strings"
 
 	"github.com/Ensono/stacks-cli/internal/models"
 	"github.com/Ensono/stacks-cli/internal/util"
@@ -23,7 +24,7 @@ type InputConfig struct {
 	Business     Business      `mapstructure:"business"`
 	Cloud        Cloud         `mapstructure:"cloud"`
 	Network      Network       `mapstructure:"network"`
-	Pipeline     string        `mapstructure:"pipeline"`
+	Pipeline     string        `mapstructure:"pipeline" schema:"pipeline"`
 	Project      []Project     `mapstructure:"project"`
 	Terraform    Terraform     `mapstructure:"terraform"`
 	SettingsFile string        `mapstructure:"settingsfile" json:",omitempty"`
@@ -31,11 +32,44 @@ type InputConfig struct {
 	Overrides    Overrides     `mapstructure:"overrides"`
 	Environment  []Environment `mapstructure:"environment"`
 
+	// Variables are free-form values that are available to all templates as `.Vars`
+	Variables map[string]string `mapstructure:"variables" yaml:",omitempty"`
+
 	// Set values to accept from the command line when running setup
 	Global  bool     `mapstructure:"global" yaml:"-"`
 	Folders []string `mapstructure:"folders" yaml:"-"`
 }
 
+// SetVariables adds the variables to the global variables, replacing any that
+// have already been set. The keys are lowercased so that they are the same as the
+// keys of the variables that are read from the configuration file
+func (ic *InputConfig) SetVariables(vars map[string]string) {
+
+	if len(vars) == 0 {
+		return
+	}
+
+	if ic.Variables == nil {
+		ic.Variables = make(map[string]string)
+	}
+
+	for key, value := range vars {
+		ic.Variables[strings.ToLower(key)] = value
+	}
+}
+
+// SetProperties adds the properties to the map of framework properties of each project,
+// replacing any that have already been set. The keys are lowercased in the same way as the
+// keys of the properties that are read from the configuration file
+func (ic *InputConfig) SetProperties(props map[string]string) {
+
+	for i := range ic.Project {
+		for key, value := range props {
+			ic.Project[i].Framework.Properties.Set(strings.ToLower(key), value)
+		}
+	}
+}
+
 // CheckFrameworks iterates around each of the projects and builds up a list of the frameworks
 // that have been specified. It will then check that each of the framework binaries
 // are present in the path.
@@ -52,10 +52,7 @@ func TestNonExistentFrameworkBinary(t *testing.T) {
 	defer cleanup(t)
 
 	// set the path to the tempDir so that no files can be found
-	err := os.Setenv("PATH", tempDir)
-	if err != nil {
-		t.Errorf("Unable to set PATH environment variable")
-	}
+	t.Setenv("PATH", tempDir)
 
 	// create a project
 	project := Project{
@@ -71,7 +68,7 @@ func TestNonExistentFrameworkBinary(t *testing.T) {
 	config.Init()
 
 	// get the static data and unmarshal into a config object
-	err = yaml.Unmarshal(config.Internal.GetFileContent("config"), &config)
+	err := yaml.Unmarshal(config.Internal.GetFileContent("config"), &config)
 	if err != nil {
 		t.Errorf("Error parsing the framework definitions: %s", err.Error())
 	}
@@ -89,10 +86,7 @@ func TestIncorrectFrameworkSet(t *testing.T) {
 	defer cleanup(t)
 
 	// set the path to the tempDir so that no files can be found
-	err := os.Setenv("PATH", tempDir)
-	if err != nil {
-		t.Errorf("Unable to set PATH environment variable")
-	}
+	t.Setenv("PATH", tempDir)
 
 	// create a project
 	project := Project{
@@ -119,10 +113,7 @@ func TestMultipleFrameworks(t *testing.T) {
 	defer cleanup(t)
 
 	// set the path to the tempDir so that no files can be found
-	err := os.Setenv("PATH", tempDir)
-	if err != nil {
-		t.Errorf("Unable to set PATH environment variable")
-	}
+	t.Setenv("PATH", tempDir)
 
 	// create a project
 	projects := []Project{
@@ -145,7 +136,7 @@ func TestMultipleFrameworks(t *testing.T) {
 	config.Init()
 
 	// get the static data and unmarshal into a config object
-	err = yaml.Unmarshal(config.Internal.GetFileContent("config"), &config)
+	err := yaml.Unmarshal(config.Internal.GetFileContent("config"), &config)
 	if err != nil {
 		t.Errorf("Error parsing the framework definitions: %s", err.Error())
 	}
@@ -231,3 +222,37 @@ func TestValidateInput(t *testing.T) {
 		}
 	}
 }
+
+func TestSetVariables(t *testing.T) {
+
+	input := InputConfig{
+		Variables: map[string]string{
+			"costcentre": "1234",
+			"team":       "platform",
+		},
+	}
+
+	// the keys are lowercased so that they replace the variables from the configuration file
+	input.SetVariables(map[string]string{"costCentre": "5678", "KeyVault": "kv-tigerfest"})
+
+	assert.Equal(t, map[string]string{
+		"costcentre": "5678",
+		"team":       "platform",
+		"keyvault":   "kv-tigerfest",
+	}, input.Variables)
+}
+
+func TestSetProperties(t *testing.T) {
+
+	input := InputConfig{
+		Project: []Project{
+			{Name: "api", Framework: Framework{Properties: Properties{List: []string{"--verbose"}}}},
+			{Name: "infra", Framework: Framework{Properties: Properties{Values: map[string]string{"auth": "false", "region": "westeurope"}}}},
+		},
+	}
+
+	input.SetProperties(map[string]string{"Auth": "true"})
+
+	assert.Equal(t, Properties{Values: map[string]string{"auth": "true"}, List: []string{"--verbose"}}, input.Project[0].Framework.Properties)
+	assert.Equal(t, Properties{Values: map[string]string{"auth": "true", "region": "westeurope"}}, input.Project[1].Framework.Properties)
+}
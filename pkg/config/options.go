@@ -1,5 +1,11 @@
 package config
 
+import (
+	"time"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+)
+
 // Options holds the options for the CLI, such as turning on cmd logging
 type Options struct {
 	CmdLog       bool   `mapstructure:"cmdlog"`
@@ -9,7 +15,63 @@ type Options struct {
 	Force        bool   `mapstructure:"force" yaml:"-"`
 	NoBanner     bool   `mapstructure:"nobanner"`
 	NoCLIVersion bool   `mapstructure:"nocliversion"`
-	Token        string `mapstructure:"token" json:"-"`
+	Token        Secret `mapstructure:"token" json:"-"`
+	GitHubHost   string `mapstructure:"githubhost" yaml:",omitempty"`
+	GitHubAPI    string `mapstructure:"githubapi" yaml:",omitempty"`
+	CABundle     string `mapstructure:"cabundle" yaml:",omitempty"`
+	Timeout      int    `mapstructure:"timeout" yaml:",omitempty"`
+	Retries      int    `mapstructure:"retries" yaml:",omitempty"`
 	OnlineHelp   bool   `mapstructure:"onlinehelp" json:"-"`
 	NoScaffold   bool   `mapstructure:"noscaffold" json:"-"`
+
+	// NoVerify is not written to saved configuration files, so that package verification is
+	// only turned off for the run in which it has been requested
+	NoVerify bool `mapstructure:"noverify" yaml:"-"`
+
+	// limits, in megabytes, on the size of archives that are downloaded and extracted
+	MaxArchiveSize  int `mapstructure:"maxarchivesize" yaml:",omitempty"`
+	MaxExtractSize  int `mapstructure:"maxextractsize" yaml:",omitempty"`
+	MaxExtractFiles int `mapstructure:"maxextractfiles" yaml:",omitempty"`
+
+	// credentials for the OCI registries that packages are pulled from
+	Registries []Registry `mapstructure:"registries" yaml:",omitempty"`
+}
+
+// GetGitHubAPI returns the base URL of the GitHub API that has been configured globally.
+// If the API has not been set it is derived from the GitHub host
+func (o *Options) GetGitHubAPI() string {
+	if o.GitHubAPI != "" {
+		return o.GitHubAPI
+	}
+
+	return util.GitHubAPIBase(o.GitHubHost)
+}
+
+// GetGitHubHost returns the host of the GitHub service, which is github.com unless a
+// GitHub Enterprise Server host has been set
+func (o *Options) GetGitHubHost() string {
+	return util.GitHubHostName(o.GitHubHost)
+}
+
+// IsGitHubEnterprise states if a GitHub Enterprise Server host has been set
+func (o *Options) IsGitHubEnterprise() bool {
+	return o.GetGitHubHost() != util.GitHubHost
+}
+
+// GetTimeout returns the timeout for network requests, which is set in seconds
+func (o *Options) GetTimeout() time.Duration {
+	return time.Duration(o.Timeout) * time.Second
+}
+
+// GetMaxArchiveSize returns the maximum size, in bytes, of an archive that can be downloaded
+func (o *Options) GetMaxArchiveSize() int64 {
+	return int64(o.MaxArchiveSize) * 1024 * 1024
+}
+
+// GetExtractLimits returns the limits that are applied when an archive is extracted
+func (o *Options) GetExtractLimits() util.ExtractLimits {
+	return util.ExtractLimits{
+		MaxSize:  int64(o.MaxExtractSize) * 1024 * 1024,
+		MaxFiles: o.MaxExtractFiles,
+	}
 }
@@ -1,10 +1,47 @@
 package config
 
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+)
+
 type Package struct {
-	ID      string `mapstructure:"id" yaml:"id"`
-	Name    string `mapstructure:"name" yaml:"name"`
-	Path    string `mapstructure:"path" yaml:"path"`
-	Type    string `mapstructure:"type" yaml:"type"`
-	URL     string `mapstructure:"url" yaml:"url"`
-	Version string `mapstructure:"version" yaml:"version"`
+	ID              string `mapstructure:"id" yaml:"id" json:"id"`
+	Name            string `mapstructure:"name" yaml:"name" json:"name"`
+	Path            string `mapstructure:"path" yaml:"path" json:"path"`
+	Type            string `mapstructure:"type" yaml:"type" json:"type"`
+	URL             string `mapstructure:"url" yaml:"url" json:"url"`
+	Version         string `mapstructure:"version" yaml:"version" json:"version"`
+	Subpath         string `mapstructure:"subpath" yaml:"subpath,omitempty" json:"subpath,omitempty"`                            // Directory within the package that contains the template
+	GitHubAPI       string `mapstructure:"github_api" yaml:"github_api,omitempty" json:"github_api,omitempty"`                   // Base URL of the GitHub API for the repository
+	SHA256          string `mapstructure:"sha256" yaml:"sha256,omitempty" json:"sha256,omitempty"`                               // Checksum of an archive package
+	StripComponents int    `mapstructure:"strip_components" yaml:"strip_components,omitempty" json:"strip_components,omitempty"` // Number of leading directories to remove from an archive or oci package
+}
+
+// GetGitHubAPI returns the base URL of the GitHub API to use for the package. The API set
+// on the package takes precedence, followed by the global API if the package is on the
+// configured GitHub host. Otherwise the API is derived from the host of the package URL
+func (p *Package) GetGitHubAPI(options Options) string {
+
+	if p.GitHubAPI != "" {
+		return p.GitHubAPI
+	}
+
+	var host string
+	if u, err := url.Parse(p.URL); err == nil {
+		host = u.Host
+	}
+
+	globalHost := options.GitHubHost
+	if globalHost == "" {
+		globalHost = util.GitHubHost
+	}
+
+	if host == "" || strings.EqualFold(host, strings.TrimSuffix(strings.TrimPrefix(globalHost, "https://"), "/")) {
+		return options.GetGitHubAPI()
+	}
+
+	return util.GitHubAPIBase(host)
 }
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageGetGitHubAPI(t *testing.T) {
+
+	tables := []struct {
+		pkg     Package
+		options Options
+		test    string
+		msg     string
+	}{
+		{
+			Package{URL: "https://github.com/Ensono/stacks-dotnet"},
+			Options{},
+			"https://api.github.com",
+			"Public GitHub repositories should use api.github.com",
+		},
+		{
+			Package{URL: "https://github.example.com/Ensono/stacks-dotnet"},
+			Options{},
+			"https://github.example.com/api/v3",
+			"The API should be derived from the host of the package",
+		},
+		{
+			Package{URL: "https://github.example.com/Ensono/stacks-dotnet", GitHubAPI: "https://api.example.com"},
+			Options{GitHubHost: "github.example.com", GitHubAPI: "https://ghes.example.com/api/v3"},
+			"https://api.example.com",
+			"The API on the package should take precedence",
+		},
+		{
+			Package{URL: "https://github.example.com/Ensono/stacks-dotnet"},
+			Options{GitHubHost: "github.example.com", GitHubAPI: "https://ghes.example.com/api/v3"},
+			"https://ghes.example.com/api/v3",
+			"The global API should be used for packages on the global host",
+		},
+		{
+			Package{URL: "https://github.com/Ensono/stacks-dotnet"},
+			Options{GitHubHost: "github.example.com"},
+			"https://api.github.com",
+			"The global host should not affect packages on other hosts",
+		},
+	}
+
+	for _, table := range tables {
+		assert.Equal(t, table.test, table.pkg.GetGitHubAPI(table.options), table.msg)
+	}
+}
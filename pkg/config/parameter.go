@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Ensono/stacks-cli/internal/util"
+)
+
+// Parameter is an input that a template needs, which is declared in the `parameters`
+// section of the project settings file. The value is supplied using the project variables
+type Parameter struct {
+	Name        string   `mapstructure:"name"`
+	Type        string   `mapstructure:"type"`
+	Description string   `mapstructure:"description"`
+	Default     string   `mapstructure:"default"`
+	Required    *bool    `mapstructure:"required"`
+	Allowed     []string `mapstructure:"allowed"`
+	Regex       string   `mapstructure:"regex" schema:"regex"`
+}
+
+// ParameterError holds the problem with a single parameter
+type ParameterError struct {
+	Name    string
+	Message string
+}
+
+func (e ParameterError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Message)
+}
+
+// GetKey returns the key that the value of the parameter is stored under in the variables.
+// The configuration is read without case, so the name is lowercased
+func (p *Parameter) GetKey() string {
+	return strings.ToLower(p.Name)
+}
+
+// IsRequired states if a value must be supplied for the parameter. If this has not been
+// set then a value is required when the parameter does not have a default
+func (p *Parameter) IsRequired() bool {
+	if p.Required != nil {
+		return *p.Required
+	}
+
+	return p.Default == ""
+}
+
+// GetType returns the type of the parameter, with a default of string
+func (p *Parameter) GetType() string {
+	if p.Type == "" {
+		return "string"
+	}
+
+	return strings.ToLower(p.Type)
+}
+
+// Validate checks that the value is of the correct type, is one of the allowed values and
+// matches the regular expression of the parameter
+func (p *Parameter) Validate(value string) error {
+
+	var err error
+
+	switch p.GetType() {
+	case "string":
+	case "int", "integer":
+		_, err = strconv.Atoi(value)
+	case "number", "float":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool", "boolean":
+		_, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("type '%s' is not supported, supported types are string, int, number and bool", p.Type)
+	}
+
+	if err != nil {
+		return fmt.Errorf("value '%s' is not a valid %s", value, p.GetType())
+	}
+
+	if len(p.Allowed) > 0 && !util.SliceContains(p.Allowed, value) {
+		return fmt.Errorf("value '%s' is not one of the allowed values: %s", value, strings.Join(p.Allowed, ", "))
+	}
+
+	if p.Regex != "" {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return fmt.Errorf("regular expression '%s' is invalid: %s", p.Regex, err.Error())
+		}
+
+		if !re.MatchString(value) {
+			return fmt.Errorf("value '%s' does not match the pattern '%s'", value, p.Regex)
+		}
+	}
+
+	return nil
+}
+
+// GetPrompt returns the prompt that is used to ask for the value of the parameter
+func (p *Parameter) GetPrompt() survey.Prompt {
+
+	message := p.Name
+	if p.Description != "" {
+		message = fmt.Sprintf("%s (%s)", p.Description, p.Name)
+	}
+
+	options := p.Allowed
+	if len(options) == 0 && (p.GetType() == "bool" || p.GetType() == "boolean") {
+		options = []string{"true", "false"}
+	}
+
+	if len(options) > 0 {
+		prompt := &survey.Select{
+			Message: message,
+			Options: options,
+		}
+		if p.Default != "" {
+			prompt.Default = p.Default
+		}
+
+		return prompt
+	}
+
+	return &survey.Input{
+		Message: message,
+		Default: p.Default,
+	}
+}
+
+// ResolveParameters checks the variables against the parameters that have been declared in
+// the settings. It returns the value for each parameter, keyed by the lowercase name and using
+// the default if a value has not been supplied, a list of the required parameters that do not
+// have a value and a list of errors for the values that are not valid. The names of the
+// variables are matched without case
+func (s *Settings) ResolveParameters(vars map[string]string) (map[string]string, []Parameter, []ParameterError) {
+
+	values := make(map[string]string)
+
+	lower := make(map[string]string, len(vars))
+	for key, value := range vars {
+		lower[strings.ToLower(key)] = value
+	}
+
+	var missing []Parameter
+	var errs []ParameterError
+
+	for _, param := range s.Parameters {
+
+		if param.Name == "" {
+			errs = append(errs, ParameterError{Name: "(unnamed)", Message: "parameter does not have a name"})
+			continue
+		}
+
+		value, ok := lower[param.GetKey()]
+		if !ok {
+			switch {
+			case param.Default != "":
+				value = param.Default
+			case param.IsRequired():
+				missing = append(missing, param)
+				continue
+			default:
+				// optional parameters without a default are set to an empty value
+				values[param.GetKey()] = ""
+				continue
+			}
+		}
+
+		if err := param.Validate(value); err != nil {
+			errs = append(errs, ParameterError{Name: param.Name, Message: err.Error()})
+			continue
+		}
+
+		values[param.GetKey()] = value
+	}
+
+	return values, missing, errs
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterValidate(t *testing.T) {
+
+	tables := []struct {
+		param Parameter
+		value string
+		valid bool
+	}{
+		{Parameter{Name: "team"}, "platform", true},
+		{Parameter{Name: "count", Type: "int"}, "3", true},
+		{Parameter{Name: "count", Type: "int"}, "three", false},
+		{Parameter{Name: "ratio", Type: "number"}, "0.5", true},
+		{Parameter{Name: "enabled", Type: "bool"}, "true", true},
+		{Parameter{Name: "enabled", Type: "bool"}, "yes", false},
+		{Parameter{Name: "size", Allowed: []string{"small", "large"}}, "small", true},
+		{Parameter{Name: "size", Allowed: []string{"small", "large"}}, "medium", false},
+		{Parameter{Name: "email", Regex: `^[^@]+@example\.com$`}, "team@example.com", true},
+		{Parameter{Name: "email", Regex: `^[^@]+@example\.com$`}, "team@other.com", false},
+		{Parameter{Name: "email", Regex: `^[`}, "team@example.com", false},
+		{Parameter{Name: "list", Type: "array"}, "a", false},
+	}
+
+	for _, table := range tables {
+		err := table.param.Validate(table.value)
+		assert.Equal(t, table.valid, err == nil, "Validation of '%s' for parameter %+v is incorrect: %v", table.value, table.param, err)
+	}
+}
+
+func TestResolveParameters(t *testing.T) {
+
+	settings := Settings{
+		Parameters: []Parameter{
+			{Name: "team", Description: "Name of the team"},
+			{Name: "region", Default: "westeurope"},
+			{Name: "count", Type: "int"},
+			{Name: "email"},
+		},
+	}
+
+	values, missing, errs := settings.ResolveParameters(map[string]string{
+		"team":  "platform",
+		"count": "many",
+	})
+
+	assert.Equal(t, map[string]string{"team": "platform", "region": "westeurope"}, values)
+
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "email", missing[0].Name)
+
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "count: value 'many' is not a valid int", errs[0].Error())
+}
+
+func TestResolveParametersOptional(t *testing.T) {
+
+	optional := false
+	required := true
+
+	settings := Settings{
+		Parameters: []Parameter{
+			{Name: "suffix", Required: &optional},
+			{Name: "count", Type: "int", Required: &optional},
+			{Name: "owner", Required: &required},
+		},
+	}
+
+	values, missing, errs := settings.ResolveParameters(map[string]string{})
+
+	// optional parameters without a value are set to an empty value and are not validated
+	assert.Equal(t, map[string]string{"suffix": "", "count": ""}, values)
+	assert.Empty(t, errs)
+
+	assert.Len(t, missing, 1)
+	assert.Equal(t, "owner", missing[0].Name)
+}
+
+func TestResolveParametersFromConfig(t *testing.T) {
+
+	data := `input:
+  variables:
+    costCentre: "1234"
+  project:
+    - name: my-webapi
+      variables:
+        Team: payments
+`
+
+	// viper lowercases the keys of the variables when the configuration is read
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(data)))
+
+	var config Config
+	require.NoError(t, v.Unmarshal(&config))
+
+	project := config.Input.Project[0]
+
+	settings := Settings{
+		Parameters: []Parameter{
+			{Name: "costCentre", Type: "int"},
+			{Name: "Team"},
+		},
+	}
+
+	values, missing, errs := settings.ResolveParameters(project.GetVariables(config.Input))
+
+	assert.Empty(t, missing)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]string{"costcentre": "1234", "team": "payments"}, values)
+}
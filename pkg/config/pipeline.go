@@ -13,11 +13,13 @@ import (
 )
 
 type Pipeline struct {
-	Type         string                `mapstructure:"type"`
+	Type         string                `mapstructure:"type" schema:"pipeline"`
 	File         []PipelineFile        `mapstructure:"files"`
 	Template     []PipelineFile        `mapstructure:"templates"`
 	Items        []string              `mapstructure:"items"`
 	Replacements []PipelineReplacement `mapstructure:"replacements"`
+	Delimiters   Delimiters            `mapstructure:"delimiters"`
+	Strict       *bool                 `mapstructure:"strict"`
 	Logger       *logrus.Logger        `mapstructure:"-"`
 }
 
@@ -27,13 +29,14 @@ func (p *Pipeline) SetLogger(logger *logrus.Logger) {
 }
 
 type PipelineFile struct {
-	Name      string `mapstructure:"name"`
-	Path      string `mapstructure:"path"`
-	NoReplace bool   `mapstructure:"noreplace"`
+	Name       string     `mapstructure:"name"`
+	Path       string     `mapstructure:"path"`
+	NoReplace  bool       `mapstructure:"noreplace"`
+	Delimiters Delimiters `mapstructure:"delimiters"`
 }
 
 type PipelineReplacement struct {
-	Pattern string `mapstructure:"pattern"`
+	Pattern string `mapstructure:"pattern" schema:"regex"`
 	Value   string `mapstructure:"value"`
 }
 
@@ -68,6 +71,26 @@ func (p *Pipeline) GetFilePath(filetype string, workingDir string, name string)
 	return path
 }
 
+// GetTemplateDelimiters returns the delimiters for the named template. If the template
+// does not set any, the delimiters for the pipeline are used. If the template has not been
+// defined, the default delimiters are returned as the built in template is used
+func (p *Pipeline) GetTemplateDelimiters(name string) Delimiters {
+
+	for _, item := range p.Template {
+		if item.Name == name {
+			return item.Delimiters.Or(p.Delimiters)
+		}
+	}
+
+	return Delimiters{}
+}
+
+// IsStrict states if the templates and replacement values of the pipeline should be
+// rendered in strict mode, which is off by default for pipelines
+func (p *Pipeline) IsStrict() bool {
+	return p.Strict != nil && *p.Strict
+}
+
 func (p *Pipeline) GetVariableTemplate(workingDir string) string {
 	var template string
 
@@ -188,7 +211,7 @@ func (p *Pipeline) ReplacePatterns(config *Config, inputs Replacements, dir stri
 			// TODO Ensure that the template for the replacement is rendered. This will mean that the value
 			// the regex is replacing can come from the inputs of the CLI
 			// render the replacement value as a template
-			replacement_value, err := config.RenderTemplate("regex", replacement.Value, inputs)
+			replacement_value, err := config.RenderTemplateWithOptions("regex", replacement.Value, inputs, RenderOptions{Delimiters: p.Delimiters, Strict: p.IsStrict()})
 			if err != nil {
 				errs = append(errs, err)
 				continue
@@ -262,3 +262,21 @@ stages:
 	assert.Equal(t, expected, string(actual))
 
 }
+
+func TestGetTemplateDelimiters(t *testing.T) {
+
+	pipeline := Pipeline{
+		Delimiters: Delimiters{Left: "[[", Right: "]]"},
+		Template: []PipelineFile{
+			{Name: "variable", Path: "build/vars.yml", Delimiters: Delimiters{Left: "<%", Right: "%>"}},
+			{Name: "build", Path: "build/build.yml"},
+		},
+	}
+
+	assert.Equal(t, Delimiters{Left: "<%", Right: "%>"}, pipeline.GetTemplateDelimiters("variable"))
+	assert.Equal(t, Delimiters{Left: "[[", Right: "]]"}, pipeline.GetTemplateDelimiters("build"))
+
+	// the built in template is used when one has not been defined, so the default
+	// delimiters are returned
+	assert.Equal(t, Delimiters{}, pipeline.GetTemplateDelimiters("missing"))
+}
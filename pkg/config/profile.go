@@ -0,0 +1,8 @@
+package config
+
+// Profile is a named set of configuration that is stored in the global configuration file
+// under `profiles.<name>`. When a profile is selected its values override those in the global
+// configuration file, but not the ones in the directory configuration files
+type Profile struct {
+	Input InputConfig `mapstructure:"input"`
+}
@@ -17,6 +17,9 @@ type Project struct {
 	SettingsFile  string        `mapstructure:"settingsfile" json:",omitempty"`
 	Cloud         Cloud         `mapstructure:"cloud"`
 
+	// Variables are free-form values for the project, which override the global variables
+	Variables map[string]string `mapstructure:"variables" yaml:",omitempty"`
+
 	Directory Directory `yaml:"-"` // Holds the workingdir and tempdir for the project
 
 	Settings Settings `yaml:"-"` // Hold the settings for the current project
@@ -30,6 +33,23 @@ func (project *Project) GetId() string {
 	return strings.Replace(strings.ToLower(project.Name), " ", "_", -1)
 }
 
+// GetVariables returns the global variables merged with the variables for the project,
+// the project variables take precedence
+func (project *Project) GetVariables(input InputConfig) map[string]string {
+
+	vars := make(map[string]string, len(input.Variables)+len(project.Variables))
+
+	for key, value := range input.Variables {
+		vars[key] = value
+	}
+
+	for key, value := range project.Variables {
+		vars[key] = value
+	}
+
+	return vars
+}
+
 // ReadSettings reads in the settings file for the current project
 // Returns the path to the file that was read for the project settings and any errors
 // that were raised
@@ -101,3 +101,26 @@ func TestSpecifiedSettingsFileExists(t *testing.T) {
 	assert.Equal(t, err, nil)
 	assert.Equal(t, expected, config.Input.Project[0].SettingsFile)
 }
+
+func TestProjectGetVariables(t *testing.T) {
+
+	input := InputConfig{
+		Variables: map[string]string{
+			"team":        "platform",
+			"cost_centre": "1234",
+		},
+	}
+
+	project := Project{
+		Variables: map[string]string{
+			"team": "payments",
+		},
+	}
+
+	vars := project.GetVariables(input)
+
+	assert.Equal(t, map[string]string{"team": "payments", "cost_centre": "1234"}, vars)
+
+	// the global variables must not be modified
+	assert.Equal(t, "platform", input.Variables["team"])
+}
@@ -0,0 +1,242 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// Properties holds the properties for a framework. They can be set as a map of key/value
+// pairs, which can be referenced individually in templates, or as a list of strings which
+// are passed to commands as they are
+type Properties struct {
+	Values map[string]string
+	List   []string
+}
+
+const (
+	// PropertyStyleFlag renders properties as `--key value`
+	PropertyStyleFlag = "flag"
+
+	// PropertyStyleMSBuild renders properties as `-p:key=value`
+	PropertyStyleMSBuild = "msbuild"
+
+	// PropertyStyleDefine renders properties as `-Dkey=value`
+	PropertyStyleDefine = "define"
+)
+
+// NewProperties creates properties from a list of strings. Items in the form `key=value`
+// are added to the map, all other items are added to the list
+func NewProperties(items []string) Properties {
+
+	props := Properties{}
+
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(item, "=")
+		if found && isPropertyKey(key) {
+			props.Set(key, value)
+		} else {
+			props.List = append(props.List, item)
+		}
+	}
+
+	return props
+}
+
+// IsEmpty states if no properties have been set
+func (p Properties) IsEmpty() bool {
+	return len(p.Values) == 0 && len(p.List) == 0
+}
+
+// Set sets the value of a property in the map
+func (p *Properties) Set(key string, value string) {
+	if p.Values == nil {
+		p.Values = make(map[string]string)
+	}
+
+	p.Values[key] = value
+}
+
+// Get returns the value of the property from the map
+func (p Properties) Get(key string) string {
+	return p.Values[key]
+}
+
+// Keys returns the keys of the map in alphabetical order
+func (p Properties) Keys() []string {
+
+	keys := make([]string, 0, len(p.Values))
+	for key := range p.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Args returns the properties as arguments for a command. The map is rendered using the
+// style and sorted by key, followed by the items in the list as they are
+func (p Properties) Args(style string) ([]string, error) {
+
+	var args []string
+
+	for _, key := range p.Keys() {
+		value := quoteProperty(p.Values[key])
+
+		switch strings.ToLower(style) {
+		case "", PropertyStyleFlag:
+			args = append(args, fmt.Sprintf("--%s %s", key, value))
+		case PropertyStyleMSBuild:
+			args = append(args, fmt.Sprintf("-p:%s=%s", key, value))
+		case PropertyStyleDefine:
+			args = append(args, fmt.Sprintf("-D%s=%s", key, value))
+		default:
+			return nil, fmt.Errorf("property style '%s' is not supported, supported styles are %s, %s and %s", style, PropertyStyleFlag, PropertyStyleMSBuild, PropertyStyleDefine)
+		}
+	}
+
+	return append(args, p.List...), nil
+}
+
+// MarshalYAML writes the properties out as a map, or as a list if only the list has been set.
+// When both have been set the map is written as the first item of the list, so that the
+// values are read back into the map and the other items are kept as they are
+func (p Properties) MarshalYAML() (interface{}, error) {
+	if len(p.List) == 0 {
+		return p.Values, nil
+	}
+
+	if len(p.Values) == 0 {
+		return p.List, nil
+	}
+
+	list := make([]interface{}, 0, len(p.List)+1)
+	list = append(list, p.Values)
+	for _, item := range p.List {
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
+// UnmarshalYAML reads the properties from either a map or a list
+func (p *Properties) UnmarshalYAML(unmarshal func(interface{}) error) error {
+
+	var list []interface{}
+	if err := unmarshal(&list); err == nil {
+		*p = propertiesFromList(list)
+		return nil
+	}
+
+	var values map[string]string
+	if err := unmarshal(&values); err != nil {
+		return fmt.Errorf("properties must be a map of key/value pairs or a list: %s", err.Error())
+	}
+
+	*p = Properties{Values: values}
+
+	return nil
+}
+
+// PropertiesHookFunc returns a decode hook that allows properties to be read from the
+// configuration as a map, a list or a comma separated string
+func PropertiesHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+
+		if to != reflect.TypeOf(Properties{}) {
+			return data, nil
+		}
+
+		switch value := data.(type) {
+		case string:
+			var list []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			return Properties{List: list}, nil
+		case []string:
+			return Properties{List: value}, nil
+		case []interface{}:
+			return propertiesFromList(value), nil
+		case map[string]interface{}:
+			values := make(map[string]string, len(value))
+			for key, item := range value {
+				values[key] = fmt.Sprintf("%v", item)
+			}
+			return Properties{Values: values}, nil
+		case map[string]string:
+			return Properties{Values: value}, nil
+		}
+
+		return data, nil
+	}
+}
+
+// propertiesFromList creates properties from a list that has been read from the configuration.
+// The items are added to the list as they are, except for maps which are added to the map of
+// values, this is how properties with both a map and a list are saved
+func propertiesFromList(items []interface{}) Properties {
+
+	props := Properties{}
+
+	for _, item := range items {
+		switch value := item.(type) {
+		case map[string]interface{}:
+			for key, v := range value {
+				props.Set(key, fmt.Sprintf("%v", v))
+			}
+		case map[interface{}]interface{}:
+			for key, v := range value {
+				props.Set(fmt.Sprintf("%v", key), fmt.Sprintf("%v", v))
+			}
+		case map[string]string:
+			for key, v := range value {
+				props.Set(key, v)
+			}
+		default:
+			props.List = append(props.List, fmt.Sprintf("%v", item))
+		}
+	}
+
+	return props
+}
+
+// DecodeHook returns the option for viper that decodes the configuration, this allows the
+// framework properties to be set as a map, a list or a comma separated string
+func DecodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		PropertiesHookFunc(),
+	))
+}
+
+// isPropertyKey states if the key can be used as the key of a property, which excludes
+// legacy items such as `-p:Name=Value` or `--name=value`
+func isPropertyKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "-") {
+		return false
+	}
+
+	return !strings.ContainsAny(key, " \t:")
+}
+
+// quoteProperty surrounds a value that contains spaces with quotes
+func quoteProperty(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return fmt.Sprintf("%q", value)
+	}
+
+	return value
+}
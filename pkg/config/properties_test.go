@@ -0,0 +1,120 @@
+package config
+
+import (
+	"testing"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProperties(t *testing.T) {
+
+	props := NewProperties([]string{"auth=true", " ", "-p:Legacy=1", "--verbose", "region=west europe"})
+
+	assert.Equal(t, map[string]string{"auth": "true", "region": "west europe"}, props.Values)
+	assert.Equal(t, []string{"-p:Legacy=1", "--verbose"}, props.List)
+	assert.Equal(t, []string{"auth", "region"}, props.Keys())
+	assert.Equal(t, "true", props.Get("auth"))
+	assert.False(t, props.IsEmpty())
+
+	assert.True(t, NewProperties([]string{""}).IsEmpty())
+}
+
+func TestPropertiesArgs(t *testing.T) {
+
+	props := Properties{
+		Values: map[string]string{"name": "my app", "auth": "true"},
+		List:   []string{"--legacy"},
+	}
+
+	tables := []struct {
+		style string
+		test  []string
+	}{
+		{"", []string{"--auth true", "--name \"my app\"", "--legacy"}},
+		{PropertyStyleFlag, []string{"--auth true", "--name \"my app\"", "--legacy"}},
+		{PropertyStyleMSBuild, []string{"-p:auth=true", "-p:name=\"my app\"", "--legacy"}},
+		{PropertyStyleDefine, []string{"-Dauth=true", "-Dname=\"my app\"", "--legacy"}},
+	}
+
+	for _, table := range tables {
+		args, err := props.Args(table.style)
+		require.NoError(t, err)
+		assert.Equal(t, table.test, args, table.style)
+	}
+
+	_, err := props.Args("unknown")
+	assert.ErrorContains(t, err, "property style 'unknown' is not supported")
+}
+
+func TestPropertiesHookFunc(t *testing.T) {
+
+	tables := []struct {
+		name  string
+		input interface{}
+		test  Properties
+	}{
+		{"string", "a=1, --b", Properties{List: []string{"a=1", "--b"}}},
+		{"list", []interface{}{"--a", 1}, Properties{List: []string{"--a", "1"}}},
+		{"list with map", []interface{}{map[string]interface{}{"auth": true}, "a=1"}, Properties{Values: map[string]string{"auth": "true"}, List: []string{"a=1"}}},
+		{"map", map[string]interface{}{"auth": true}, Properties{Values: map[string]string{"auth": "true"}}},
+	}
+
+	for _, table := range tables {
+
+		var framework Framework
+
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook: PropertiesHookFunc(),
+			Result:     &framework,
+		})
+		require.NoError(t, err)
+
+		err = decoder.Decode(map[string]interface{}{"properties": table.input})
+		require.NoError(t, err, table.name)
+
+		assert.Equal(t, table.test, framework.Properties, table.name)
+	}
+}
+
+func TestPropertiesYAML(t *testing.T) {
+
+	framework := Framework{
+		Type:       "dotnet",
+		Properties: Properties{Values: map[string]string{"auth": "true"}},
+	}
+
+	data, err := yaml.Marshal(&framework)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "properties:\n  auth: \"true\"")
+
+	var result Framework
+	err = yaml.Unmarshal(data, &result)
+	require.NoError(t, err)
+	assert.Equal(t, framework.Properties, result.Properties)
+
+	err = yaml.Unmarshal([]byte("properties:\n- --legacy\n"), &result)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--legacy"}, result.Properties.List)
+
+	// properties with both a map and a list are written out as a list that starts with the map
+	framework.Properties = Properties{
+		Values: map[string]string{"auth": "true"},
+		List:   []string{"a=1", "--verbose"},
+	}
+	data, err = yaml.Marshal(&framework)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "properties:\n- auth: \"true\"\n- a=1\n- --verbose")
+
+	result = Framework{}
+	err = yaml.Unmarshal(data, &result)
+	require.NoError(t, err)
+	assert.Equal(t, framework.Properties, result.Properties)
+
+	// empty properties are not written out
+	data, err = yaml.Marshal(&Framework{Type: "java"})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "properties")
+}
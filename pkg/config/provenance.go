@@ -0,0 +1,352 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	yaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+const (
+	// SourceFile states that a value was read from a configuration file
+	SourceFile = "file"
+
+	// SourceEnv states that a value was read from an environment variable
+	SourceEnv = "env"
+
+	// SourceFlag states that a value was set on the command line
+	SourceFlag = "flag"
+
+	// SourceDefault states that a value is the default or was set by the CLI
+	SourceDefault = "default"
+)
+
+// simpleKey matches keys that can be used in a YAML path without being quoted
+var simpleKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Source states where a value in the configuration came from
+type Source struct {
+	Type string `json:"type"`
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func (s Source) String() string {
+	switch s.Type {
+	case SourceFile:
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	case SourceEnv, SourceFlag:
+		return fmt.Sprintf("%s %s", s.Type, s.Name)
+	}
+
+	return SourceDefault
+}
+
+// Provenance records where each value in the configuration was set, so that the merged
+// configuration can be shown with the source of each value. Keys are in dotted notation,
+// in lower case, with the index of items in lists, e.g. `input.project[0].name`
+type Provenance struct {
+	files map[string]Source
+	env   map[string]Source
+	flags map[string]Source
+}
+
+// NewProvenance creates an empty provenance
+func NewProvenance() *Provenance {
+	return &Provenance{
+		files: make(map[string]Source),
+		env:   make(map[string]Source),
+		flags: make(map[string]Source),
+	}
+}
+
+// AddFile records the values that are set in the configuration file. Files must be added
+// in the order in which they were merged, maps are merged with the values from previous
+// files and lists replace them
+func (p *Provenance) AddFile(path string) error {
+	return p.AddFileSection(path, "")
+}
+
+// AddFileSection records the values that are set in a section of the configuration file, as
+// if they had been set at the root of the file, e.g. the values of a profile
+func (p *Provenance) AddFileSection(path string, section string) error {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read configuration file: %s", err.Error())
+	}
+
+	doc, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return fmt.Errorf("unable to parse configuration file '%s': %s", path, err.Error())
+	}
+
+	for _, d := range doc.Docs {
+		body := d.Body
+		if section != "" {
+			body = findSection(body, strings.Split(strings.ToLower(section), "."))
+		}
+
+		if values, ok := mappingValues(body); ok {
+			for _, mv := range values {
+				p.addNode(path, joinPath("", mappingKey(mv)), mv.Key, mv.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findSection returns the value of the map at the path of keys
+func findSection(node ast.Node, keys []string) ast.Node {
+
+	for _, key := range keys {
+		if anchor, ok := node.(*ast.AnchorNode); ok {
+			node = anchor.Value
+		}
+
+		values, _ := mappingValues(node)
+
+		node = nil
+		for _, mv := range values {
+			if mappingKey(mv) == key {
+				node = mv.Value
+				break
+			}
+		}
+	}
+
+	if anchor, ok := node.(*ast.AnchorNode); ok {
+		node = anchor.Value
+	}
+
+	return node
+}
+
+// addNode records the source of the value and all of the values beneath it
+func (p *Provenance) addNode(file string, key string, keyNode ast.Node, node ast.Node) {
+
+	if anchor, ok := node.(*ast.AnchorNode); ok {
+		node = anchor.Value
+	}
+
+	if _, ok := node.(*ast.NullNode); ok || node == nil {
+		return
+	}
+
+	// values that are not maps replace anything set by a previous file
+	values, isMap := mappingValues(node)
+	if !isMap {
+		p.remove(key)
+	}
+
+	source := Source{Type: SourceFile, File: file}
+	if tk := keyNode.GetToken(); tk != nil && tk.Position != nil {
+		source.Line = tk.Position.Line
+	}
+	p.files[key] = source
+
+	switch n := node.(type) {
+	case *ast.SequenceNode:
+		for i, item := range n.Values {
+			p.addNode(file, fmt.Sprintf("%s[%d]", key, i), item, item)
+		}
+	default:
+		for _, mv := range values {
+			p.addNode(file, joinPath(key, mappingKey(mv)), mv.Key, mv.Value)
+		}
+	}
+}
+
+// remove deletes the source of the key and all of the keys beneath it
+func (p *Provenance) remove(key string) {
+	for existing := range p.files {
+		if existing == key || strings.HasPrefix(existing, key+".") || strings.HasPrefix(existing, key+"[") {
+			delete(p.files, existing)
+		}
+	}
+}
+
+// AddEnv records that the key has been set by the named environment variable
+func (p *Provenance) AddEnv(key string, name string) {
+	p.env[strings.ToLower(key)] = Source{Type: SourceEnv, Name: name}
+}
+
+// AddFlag records that the key has been set by the named command line flag
+func (p *Provenance) AddFlag(key string, name string) {
+	p.flags[strings.ToLower(key)] = Source{Type: SourceFlag, Name: name}
+}
+
+// Lookup returns the source of the value for the key. Flags take precedence over
+// environment variables, which take precedence over files. Lists are set as a whole, so
+// the source of a value in a list is the source of the list
+func (p *Provenance) Lookup(key string) Source {
+
+	keys := parentKeys(strings.ToLower(key))
+
+	for _, sources := range []map[string]Source{p.flags, p.env, p.files} {
+		for _, k := range keys {
+			if source, ok := sources[k]; ok {
+				return source
+			}
+		}
+	}
+
+	return Source{Type: SourceDefault}
+}
+
+// YAML returns the input configuration as YAML, with the source of each value as a comment
+func (p *Provenance) YAML(input InputConfig) ([]byte, error) {
+
+	output := Output{Input: input}
+
+	leaves, err := configLeaves(output)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := yaml.CommentMap{}
+	for _, leaf := range leaves {
+		if path, ok := yamlPath(leaf); ok {
+			comments[path] = yaml.LineComment(" " + p.Lookup(strings.Join(leaf, "")).String())
+		}
+	}
+
+	return yaml.MarshalWithOptions(output, yaml.WithComment(comments))
+}
+
+// JSON returns the input configuration as JSON, along with a map of the source of
+// each value
+func (p *Provenance) JSON(input InputConfig) ([]byte, error) {
+
+	output := Output{Input: input}
+
+	leaves, err := configLeaves(output)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := yaml.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[string]interface{}
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]Source, len(leaves))
+	for _, leaf := range leaves {
+		key := strings.Join(leaf, "")
+		sources[key] = p.Lookup(key)
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"config":  config,
+		"sources": sources,
+	}, "", "  ")
+}
+
+// configLeaves returns the path to every value in the configuration. Each path is a list
+// of segments, keys are preceded by a `.` apart from the first and indexes are in brackets
+func configLeaves(output Output) ([][]string, error) {
+
+	data, err := yaml.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert configuration to YAML: %s", err.Error())
+	}
+
+	doc, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration: %s", err.Error())
+	}
+
+	var leaves [][]string
+
+	var walk func(node ast.Node, path []string)
+	walk = func(node ast.Node, path []string) {
+
+		if values, ok := mappingValues(node); ok {
+			for _, mv := range values {
+				segment := mappingKey(mv)
+				if len(path) > 0 {
+					segment = "." + segment
+				}
+				walk(mv.Value, append(append([]string{}, path...), segment))
+			}
+			return
+		}
+
+		if seq, ok := node.(*ast.SequenceNode); ok && len(seq.Values) > 0 {
+			for i, item := range seq.Values {
+				walk(item, append(append([]string{}, path...), fmt.Sprintf("[%d]", i)))
+			}
+			return
+		}
+
+		leaves = append(leaves, path)
+	}
+
+	for _, d := range doc.Docs {
+		walk(d.Body, nil)
+	}
+
+	return leaves, nil
+}
+
+// yamlPath returns the path to the value in the form used for YAML comments,
+// e.g. `$.input.project[0].name`
+func yamlPath(leaf []string) (string, bool) {
+
+	path := strings.Builder{}
+	path.WriteString("$")
+
+	for _, segment := range leaf {
+		if strings.HasPrefix(segment, "[") {
+			path.WriteString(segment)
+			continue
+		}
+
+		key := strings.TrimPrefix(segment, ".")
+		if !simpleKey.MatchString(key) {
+			if strings.Contains(key, "'") {
+				return "", false
+			}
+			key = fmt.Sprintf("'%s'", key)
+		}
+
+		path.WriteString(".")
+		path.WriteString(key)
+	}
+
+	return path.String(), true
+}
+
+// parentKeys returns the key and each of the lists that contain it, starting with the key.
+// Maps are merged so a value in a map does not come from the same place as its parent
+func parentKeys(key string) []string {
+
+	keys := []string{key}
+
+	for i := len(key) - 1; i > 0; i-- {
+		if key[i] == '[' {
+			keys = append(keys, key[:i])
+		}
+	}
+
+	return keys
+}
+
+// mappingKey returns the key of the map value in lower case, as the configuration is
+// read without case
+func mappingKey(mv *ast.MappingValueNode) string {
+	return strings.ToLower(strings.TrimSpace(mv.Key.String()))
+}
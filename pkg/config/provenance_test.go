@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenanceLookup(t *testing.T) {
+
+	dir := t.TempDir()
+
+	home := filepath.Join(dir, "home.yml")
+	err := os.WriteFile(home, []byte(`input:
+  business:
+    company: Ensono
+    domain: core
+  project:
+    - name: first
+    - name: second
+`), 0o644)
+	require.NoError(t, err)
+
+	local := filepath.Join(dir, "local.yml")
+	err = os.WriteFile(local, []byte(`input:
+  business:
+    domain: platform
+  project:
+    - name: replaced
+`), 0o644)
+	require.NoError(t, err)
+
+	p := NewProvenance()
+	require.NoError(t, p.AddFile(home))
+	require.NoError(t, p.AddFile(local))
+
+	p.AddEnv("input.terraform.backend.group", "ENSONOSTACKS_INPUT_TERRAFORM_BACKEND_GROUP")
+	p.AddFlag("input.business.company", "--company")
+
+	tables := []struct {
+		key  string
+		test string
+	}{
+		{"input.business.company", "flag --company"},
+		{"input.business.domain", local + ":3"},
+		{"input.project[0].name", local + ":5"},
+		{"input.terraform.backend.group", "env ENSONOSTACKS_INPUT_TERRAFORM_BACKEND_GROUP"},
+		{"input.cloud.region", "default"},
+	}
+
+	for _, table := range tables {
+		assert.Equal(t, table.test, p.Lookup(table.key).String(), table.key)
+	}
+
+	assert.Error(t, p.AddFile(filepath.Join(dir, "missing.yml")))
+}
+
+func TestProvenanceOutput(t *testing.T) {
+
+	p := NewProvenance()
+	p.AddFlag("input.business.company", "--company")
+
+	input := InputConfig{
+		Business:  Business{Company: "Ensono"},
+		Variables: map[string]string{"cost.centre": "1234"},
+	}
+
+	data, err := p.YAML(input)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "company: Ensono # flag --company")
+	assert.Contains(t, string(data), "cost.centre: \"1234\" # default")
+
+	data, err = p.JSON(input)
+	require.NoError(t, err)
+
+	var result struct {
+		Config  map[string]interface{} `json:"config"`
+		Sources map[string]Source      `json:"sources"`
+	}
+	require.NoError(t, json.Unmarshal(data, &result))
+
+	assert.Contains(t, result.Config, "input")
+	assert.Equal(t, Source{Type: SourceFlag, Name: "--company"}, result.Sources["input.business.company"])
+	assert.Equal(t, Source{Type: SourceDefault}, result.Sources["input.variables.cost.centre"])
+}
+
+func TestProvenanceFileSection(t *testing.T) {
+
+	dir := t.TempDir()
+
+	home := filepath.Join(dir, "home.yml")
+	err := os.WriteFile(home, []byte(`input:
+  business:
+    company: Ensono
+profiles:
+  ClientA:
+    input:
+      business:
+        company: ClientA
+`), 0o644)
+	require.NoError(t, err)
+
+	p := NewProvenance()
+	require.NoError(t, p.AddFile(home))
+	require.NoError(t, p.AddFileSection(home, "profiles.clienta"))
+
+	assert.Equal(t, home+":8", p.Lookup("input.business.company").String())
+
+	// a section that does not exist does not record any values
+	require.NoError(t, p.AddFileSection(home, "profiles.missing"))
+	assert.Equal(t, home+":8", p.Lookup("input.business.company").String())
+}
@@ -0,0 +1,19 @@
+package config
+
+import "strings"
+
+// Registry holds the credentials that are used to authenticate with an OCI registry.
+// The credentials are only sent to the registry host, or the AuthHost if the registry
+// issues tokens from a different host, and only over HTTPS
+type Registry struct {
+	Host     string `mapstructure:"host"`
+	Username string `mapstructure:"username" yaml:",omitempty"`
+	Token    Secret `mapstructure:"token" json:"-"`
+	AuthHost string `mapstructure:"authhost" yaml:",omitempty"`
+}
+
+// AllowsAuthHost states if the credentials can be sent to the host that issues tokens
+// for the registry
+func (r Registry) AllowsAuthHost(host string) bool {
+	return strings.EqualFold(host, r.Host) || (r.AuthHost != "" && strings.EqualFold(host, r.AuthHost))
+}
@@ -0,0 +1,27 @@
+package config
+
+import "strings"
+
+// DefaultRenderSuffix is the suffix of files in a project that are rendered as templates
+const DefaultRenderSuffix = ".tmpl"
+
+// Render holds the settings that determine which files in the project are rendered
+// through the template engine after the operations have been performed
+type Render struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Suffix  string   `mapstructure:"suffix"` // suffix of the files to render, which is removed from the rendered file
+	Items   []string `mapstructure:"items"`  // glob patterns of files that are rendered in place
+}
+
+// GetSuffix returns the suffix of the template files, with a default of `.tmpl`
+func (r *Render) GetSuffix() string {
+	if r.Suffix == "" {
+		return DefaultRenderSuffix
+	}
+
+	if !strings.HasPrefix(r.Suffix, ".") {
+		return "." + r.Suffix
+	}
+
+	return r.Suffix
+}
@@ -1,6 +1,54 @@
 package config
 
+import "path/filepath"
+
 type Replacements struct {
 	Input   InputConfig
 	Project Project
+
+	// Projects holds all of the projects in the configuration, keyed by the ID of the
+	// project, so that a project can reference the values of another
+	Projects map[string]Project
+
+	// Environments holds the environments that have been set in the configuration
+	Environments []Environment
+
+	// Vars holds the variables for the project, which are the global variables
+	// overridden by those set for the project
+	Vars map[string]string
+
+	// Properties holds the key/value properties of the framework for the project
+	Properties map[string]string
+}
+
+// NewReplacements creates the object that is passed to templates for the project
+func NewReplacements(input InputConfig, project Project) Replacements {
+	return Replacements{
+		Input:        input,
+		Project:      project,
+		Projects:     getProjects(input, project),
+		Environments: input.Environment,
+		Vars:         project.GetVariables(input),
+		Properties:   project.Framework.Properties.Values,
+	}
+}
+
+// getProjects returns all of the projects keyed by their ID. The working directory of
+// projects that have not been processed yet is set to where they will be created and the
+// current project is used as it is, as it has the most up to date values
+func getProjects(input InputConfig, current Project) map[string]Project {
+
+	projects := make(map[string]Project, len(input.Project)+1)
+
+	for _, project := range input.Project {
+		if project.Directory.WorkingDir == "" && project.Name != "" {
+			project.Directory.WorkingDir = filepath.Join(input.Directory.WorkingDir, project.Name)
+		}
+
+		projects[project.GetId()] = project
+	}
+
+	projects[current.GetId()] = current
+
+	return projects
 }
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReplacementsProjects(t *testing.T) {
+
+	input := InputConfig{
+		Directory: Directory{WorkingDir: "/projects"},
+		Project: []Project{
+			{
+				Name:          "Core Infra",
+				SourceControl: SourceControl{URL: "https://github.com/example/infra"},
+				Cloud:         Cloud{ResourceGroup: "rg-infra"},
+			},
+			{Name: "webapi"},
+		},
+		Environment: []Environment{{Name: "dev"}, {Name: "prod", DependsOn: []string{"dev"}}},
+	}
+
+	// the current project has been processed so has its own working directory
+	current := input.Project[1]
+	current.Directory.WorkingDir = "/tmp/webapi"
+
+	replacements := NewReplacements(input, current)
+
+	require.Len(t, replacements.Projects, 2)
+	assert.Equal(t, filepath.Join("/projects", "Core Infra"), replacements.Projects["core_infra"].Directory.WorkingDir)
+	assert.Equal(t, "/tmp/webapi", replacements.Projects["webapi"].Directory.WorkingDir)
+	assert.Equal(t, input.Environment, replacements.Environments)
+
+	// check that the values of another project can be used in a template
+	config := Config{}
+	tmpl := `{{ .Projects.core_infra.SourceControl.URL }} {{ .Projects.core_infra.Cloud.ResourceGroup }}{{ range .Environments }} {{ .Name }}{{ end }}`
+
+	result, err := config.RenderTemplate("projects", tmpl, replacements)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/example/infra rg-infra dev prod", result)
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const (
+	// SchemaDraft is the version of JSON Schema that the schemas are written in
+	SchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+	// SchemaConfig is the name of the schema for the CLI configuration file, `stacks.yml`
+	SchemaConfig = "config"
+
+	// SchemaSettings is the name of the schema for the project settings file, `stackscli.yml`
+	SchemaSettings = "settings"
+)
+
+// Schema is a JSON Schema that describes a configuration file. It only contains the
+// keywords that are required to describe the configuration structs
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// ConfigFile is the layout of the CLI configuration file, which is used to generate
+// the schema for the file
+type ConfigFile struct {
+	Input    InputConfig        `mapstructure:"input"`
+	Stacks   Stacks             `mapstructure:"stacks"`
+	Profile  string             `mapstructure:"profile"`
+	Profiles map[string]Profile `mapstructure:"profiles"`
+}
+
+// GetSchemaNames returns the names of the schemas that can be generated
+func GetSchemaNames() []string {
+	return []string{SchemaConfig, SchemaSettings}
+}
+
+// NewSchema generates the schema for the named configuration file
+func NewSchema(name string) (*Schema, bool) {
+
+	var schema *Schema
+
+	switch strings.ToLower(name) {
+	case SchemaConfig:
+		schema = schemaFor(reflect.TypeOf(ConfigFile{}))
+		schema.Title = "Stacks CLI configuration file"
+	case SchemaSettings:
+		schema = schemaFor(reflect.TypeOf(Settings{}))
+		schema.Title = "Stacks project settings file"
+	default:
+		return nil, false
+	}
+
+	schema.Draft = SchemaDraft
+
+	return schema, true
+}
+
+// JSON returns the schema as indented JSON
+func (s *Schema) JSON() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Property returns the schema for the named property of an object. The name is matched
+// without case as the configuration is read without case
+func (s *Schema) Property(name string) (*Schema, bool) {
+
+	for key, prop := range s.Properties {
+		if strings.EqualFold(key, name) {
+			return prop, true
+		}
+	}
+
+	if additional, ok := s.AdditionalProperties.(*Schema); ok {
+		return additional, true
+	}
+
+	return nil, false
+}
+
+// PropertyNames returns the names of the properties of an object
+func (s *Schema) PropertyNames() []string {
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// schemaFor returns the schema for the type, structs are described using the names
+// from the mapstructure tags of their fields
+func schemaFor(t reflect.Type) *Schema {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	// properties can be set as a map, a list or a comma separated string. A list can
+	// contain a map, which is how properties with both are saved
+	if t == reflect.TypeOf(Properties{}) {
+		values := &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}
+
+		return &Schema{
+			OneOf: []*Schema{
+				values,
+				{Type: "array", Items: &Schema{OneOf: []*Schema{{Type: "string"}, values}}},
+				{Type: "string"},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaFor(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	}
+
+	return &Schema{}
+}
+
+// structSchema returns the schema for a struct, fields that cannot be set from
+// the configuration are ignored. Fields that are not written to the configuration
+// file, such as the internal state and the options that only apply to a single run,
+// are also left out
+func structSchema(t reflect.Type) *Schema {
+
+	schema := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if strings.Split(field.Tag.Get("yaml"), ",")[0] == "-" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		prop := schemaFor(field.Type)
+
+		// add the extra checks that have been set on the field
+		switch field.Tag.Get("schema") {
+		case "regex":
+			prop.Format = "regex"
+		case "pipeline":
+			prop.Enum = (&Pipeline{}).GetSupported()
+		}
+
+		schema.Properties[name] = prop
+	}
+
+	return schema
+}
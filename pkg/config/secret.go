@@ -0,0 +1,153 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+)
+
+const (
+	// SecretEnvPrefix states that the secret is read from the named environment variable
+	SecretEnvPrefix = "env:"
+
+	// SecretFilePrefix states that the secret is read from the file
+	SecretFilePrefix = "file:"
+
+	// SecretCmdPrefix states that the secret is the output of the command
+	SecretCmdPrefix = "cmd:"
+)
+
+var (
+	resolvedSecrets   = map[Secret]string{}
+	resolvedSecretsMu sync.Mutex
+)
+
+// Secret is a value, such as a token, that should not be set in plain text. It can be set as a
+// reference to an environment variable, `env:GITHUB_TOKEN`, a file, `file:~/.config/gh/token`, or
+// the output of a command, `cmd:gh auth token`. References are only resolved when the value is
+// needed and the resolved values are redacted from logs and files
+type Secret string
+
+// IsReference states if the secret is a reference rather than the value itself
+func (s Secret) IsReference() bool {
+	for _, prefix := range []string{SecretEnvPrefix, SecretFilePrefix, SecretCmdPrefix} {
+		if strings.HasPrefix(string(s), prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resolve returns the value of the secret, reading it from the reference if one has been set.
+// Each reference is only resolved once and the value is registered so that it is redacted.
+// A value that has been redacted is treated as not being set
+func (s Secret) Resolve() (string, error) {
+
+	if s == "" || string(s) == util.RedactedValue {
+		return "", nil
+	}
+
+	resolvedSecretsMu.Lock()
+	defer resolvedSecretsMu.Unlock()
+
+	if value, ok := resolvedSecrets[s]; ok {
+		return value, nil
+	}
+
+	value := string(s)
+	var err error
+
+	switch {
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		name := strings.TrimPrefix(value, SecretEnvPrefix)
+		var ok bool
+		if value, ok = os.LookupEnv(name); !ok {
+			err = fmt.Errorf("environment variable '%s' is not set", name)
+		}
+
+	case strings.HasPrefix(value, SecretFilePrefix):
+		value, err = readSecretFile(strings.TrimPrefix(value, SecretFilePrefix))
+
+	case strings.HasPrefix(value, SecretCmdPrefix):
+		value, err = runSecretCmd(strings.TrimPrefix(value, SecretCmdPrefix))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret '%s': %s", s.String(), err.Error())
+	}
+
+	value = strings.TrimSpace(value)
+
+	util.AddSecret(value)
+	resolvedSecrets[s] = value
+
+	return value, nil
+}
+
+// Value returns the resolved value of the secret, or an empty string if it cannot be resolved.
+// This is used in templates, e.g. `{{ .Input.Options.Token.Value }}`
+func (s Secret) Value() string {
+	value, _ := s.Resolve()
+	return value
+}
+
+// String returns the reference, or the redacted value if the secret has been set in plain text,
+// so that the secret is not shown when it is output
+func (s Secret) String() string {
+	if s == "" || s.IsReference() {
+		return string(s)
+	}
+
+	return util.RedactedValue
+}
+
+// MarshalYAML writes out the reference rather than the value, so that secrets are not
+// saved in configuration files
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// readSecretFile reads the secret from the file, a leading `~` is replaced with the
+// home directory of the user
+func readSecretFile(path string) (string, error) {
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = filepath.Join(util.GetUserHomeDir(), strings.TrimPrefix(path, "~"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// runSecretCmd runs the command and returns its output
+func runSecretCmd(command string) (string, error) {
+
+	name, arguments, _ := strings.Cut(strings.TrimSpace(command), " ")
+	if name == "" {
+		return "", errors.New("command has not been set")
+	}
+
+	cmd, args := util.BuildCommand(name, arguments)
+
+	output, err := exec.Command(cmd, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+
+	return string(output), nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"text/template"
+	"text/template/parse"
+)
+
+// secretFields are the names of the fields, in the values that are passed to templates,
+// that hold a Secret. When they are output in a template they show the reference, or the
+// redacted value, rather than the secret itself
+var secretFields = findSecretFieldNames(reflect.TypeOf(Replacements{}), map[reflect.Type]bool{})
+
+// checkSecretFields returns an error if the template outputs a secret field directly, e.g.
+// {{ .Input.Options.Token }}, which used to output the token but now outputs the reference or
+// `********`. The value has to be output using `.Value`. Fields that are only used as the
+// condition of an `if` are allowed, as they do not output the value
+func checkSecretFields(t *template.Template) error {
+
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+
+		if node := findSecretField(tmpl.Tree.Root); node != nil {
+			location, _ := tmpl.Tree.ErrorContext(node)
+			return fmt.Errorf("template: %s: %s does not output the secret, use %s.Value instead", location, node.String(), node.String())
+		}
+	}
+
+	return nil
+}
+
+// findSecretField returns the first node in the tree that refers to a secret field
+func findSecretField(node parse.Node) parse.Node {
+
+	var children []parse.Node
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		children = n.Nodes
+	case *parse.ActionNode:
+		children = []parse.Node{n.Pipe}
+	case *parse.IfNode:
+		children = []parse.Node{n.List, n.ElseList}
+	case *parse.RangeNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.WithNode:
+		children = []parse.Node{n.Pipe, n.List, n.ElseList}
+	case *parse.TemplateNode:
+		children = []parse.Node{n.Pipe}
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			children = append(children, cmd)
+		}
+	case *parse.CommandNode:
+		children = n.Args
+	case *parse.ChainNode:
+		if isSecretField(n.Field) {
+			return n
+		}
+		children = []parse.Node{n.Node}
+	case *parse.FieldNode:
+		if isSecretField(n.Ident) {
+			return n
+		}
+	case *parse.VariableNode:
+		// the first identifier is the name of the variable, e.g. $ or $registry
+		if len(n.Ident) > 1 && isSecretField(n.Ident[1:]) {
+			return n
+		}
+	}
+
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if found := findSecretField(child); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// isSecretField states if the last identifier of a field, e.g. `.Input.Options.Token`, is
+// the name of a field that holds a secret
+func isSecretField(ident []string) bool {
+	if len(ident) == 0 {
+		return false
+	}
+
+	return secretFields[ident[len(ident)-1]]
+}
+
+// findSecretFieldNames returns the names of the fields of the type, and of the types that
+// it contains, that hold a Secret
+func findSecretFieldNames(t reflect.Type, seen map[reflect.Type]bool) map[string]bool {
+
+	names := make(map[string]bool)
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return findSecretFieldNames(t.Elem(), seen)
+	case reflect.Struct:
+	default:
+		return names
+	}
+
+	if seen[t] {
+		return names
+	}
+	seen[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if field.Type == reflect.TypeOf(Secret("")) {
+			names[field.Name] = true
+			continue
+		}
+
+		for name := range findSecretFieldNames(field.Type, seen) {
+			names[name] = true
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	yaml "github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretResolve(t *testing.T) {
+
+	t.Setenv("STACKSCLI_TEST_SECRET", "env-secret-value\n")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token")
+	err := os.WriteFile(file, []byte("file-secret-value\n"), 0600)
+	assert.Equal(t, nil, err)
+
+	tables := []struct {
+		secret   Secret
+		expected string
+		msg      string
+	}{
+		{
+			"",
+			"",
+			"An empty secret should resolve to an empty string",
+		},
+		{
+			Secret(util.RedactedValue),
+			"",
+			"A redacted secret should be treated as not being set",
+		},
+		{
+			"plain-secret-value",
+			"plain-secret-value",
+			"A plain text secret should resolve to itself",
+		},
+		{
+			"env:STACKSCLI_TEST_SECRET",
+			"env-secret-value",
+			"Secret should be read from the environment variable",
+		},
+		{
+			Secret("file:" + file),
+			"file-secret-value",
+			"Secret should be read from the file",
+		},
+		{
+			"cmd:echo cmd-secret-value",
+			"cmd-secret-value",
+			"Secret should be read from the output of the command",
+		},
+	}
+
+	for _, table := range tables {
+		value, err := table.secret.Resolve()
+
+		assert.Equal(t, nil, err, table.msg)
+		assert.Equal(t, table.expected, value, table.msg)
+
+		// the resolved value must be redacted
+		if value != "" {
+			assert.Equal(t, util.RedactedValue, util.Redact(value), table.msg)
+		}
+	}
+}
+
+func TestSecretResolveErrors(t *testing.T) {
+
+	tables := []struct {
+		secret Secret
+		msg    string
+	}{
+		{
+			"env:STACKSCLI_TEST_SECRET_MISSING",
+			"An error should be returned when the environment variable is not set",
+		},
+		{
+			Secret("file:" + filepath.Join(t.TempDir(), "missing")),
+			"An error should be returned when the file does not exist",
+		},
+		{
+			"cmd:",
+			"An error should be returned when the command has not been set",
+		},
+	}
+
+	for _, table := range tables {
+		_, err := table.secret.Resolve()
+
+		assert.NotEqual(t, nil, err, table.msg)
+	}
+}
+
+func TestSecretString(t *testing.T) {
+
+	tables := []struct {
+		secret   Secret
+		expected string
+		msg      string
+	}{
+		{
+			"",
+			"",
+			"An empty secret should be empty",
+		},
+		{
+			"env:GITHUB_TOKEN",
+			"env:GITHUB_TOKEN",
+			"A reference should be shown as it is",
+		},
+		{
+			"ghp_plaintext",
+			util.RedactedValue,
+			"A plain text secret should be redacted",
+		},
+	}
+
+	for _, table := range tables {
+		assert.Equal(t, table.expected, table.secret.String(), table.msg)
+
+		data, err := yaml.Marshal(map[string]Secret{"token": table.secret})
+		assert.Equal(t, nil, err)
+		assert.NotContains(t, string(data), "ghp_plaintext", table.msg)
+	}
+}
+
+func TestRenderTemplateSecretFields(t *testing.T) {
+
+	t.Setenv("STACKSCLI_TEST_TEMPLATE_SECRET", "template-secret-value")
+
+	cfg := Config{}
+	replacements := Replacements{}
+	replacements.Input.Options.Token = "env:STACKSCLI_TEST_TEMPLATE_SECRET"
+	replacements.Input.Options.Registries = []Registry{{Host: "ghcr.io", Token: "registry-secret"}}
+
+	tables := []struct {
+		tmpl     string
+		expected string
+		err      string
+	}{
+		{`{{ .Input.Options.Token.Value }}`, "template-secret-value", ""},
+		{`{{ if .Input.Options.Token }}set{{ end }}`, "set", ""},
+		{`{{ .Input.Options.Token }}`, "", "use .Input.Options.Token.Value instead"},
+		{`https://{{ .Input.Options.Token | quote }}@github.com`, "", "use .Input.Options.Token.Value instead"},
+		{`{{ with .Input }}{{ $.Input.Options.Token }}{{ end }}`, "", "use $.Input.Options.Token.Value instead"},
+		{`{{ range .Input.Options.Registries }}{{ .Token.Value }}{{ end }}`, "registry-secret", ""},
+		{`{{ range .Input.Options.Registries }}{{ .Token }}{{ end }}`, "", "use .Token.Value instead"},
+		{`{{ range $r := .Input.Options.Registries }}{{ $r.Token }}{{ end }}`, "", "use $r.Token.Value instead"},
+		{`{{ (index .Input.Options.Registries 0).Token }}`, "", "use (index .Input.Options.Registries 0).Token.Value instead"},
+	}
+
+	for _, table := range tables {
+		rendered, err := cfg.RenderTemplate("secret", table.tmpl, replacements)
+
+		if table.err == "" {
+			assert.Equal(t, nil, err, table.tmpl)
+			assert.Equal(t, table.expected, rendered, table.tmpl)
+		} else {
+			assert.ErrorContains(t, err, table.err, table.tmpl)
+		}
+	}
+}
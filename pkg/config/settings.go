@@ -20,6 +20,35 @@ type Settings struct {
 	Pipeline  []Pipeline        `mapstructure:"pipeline"`
 	Init      Init              `mapstructure:"init"`
 	Setup     Setup             `mapstructure:"setup"`
+	Render    Render            `mapstructure:"render"`
+
+	// Parameters are the inputs that the templates in the project need
+	Parameters []Parameter `mapstructure:"parameters"`
+
+	// Delimiters are used for all of the templates in the project, unless they are
+	// overridden for a pipeline or a pipeline template
+	Delimiters Delimiters `mapstructure:"delimiters"`
+
+	// Strict states if templates should fail to render when they reference a value that
+	// does not exist or is empty. If it is not set, the default depends on the template
+	Strict *bool `mapstructure:"strict"`
+}
+
+// GetRenderOptions returns the options for rendering templates in the project. The default
+// is used for strict mode if it has not been set in the settings file, this is on for files
+// that are rendered or renamed and off for command arguments and pipelines so that existing
+// templates continue to work
+func (s *Settings) GetRenderOptions(strictDefault bool) RenderOptions {
+
+	strict := strictDefault
+	if s.Strict != nil {
+		strict = *s.Strict
+	}
+
+	return RenderOptions{
+		Delimiters: s.Delimiters,
+		Strict:     strict,
+	}
 }
 
 // Init holds the operations that should be performed before any work
@@ -40,7 +69,21 @@ type Operation struct {
 	Arguments       string   `mapstructure:"args"`
 	Description     string   `mapstructure:"desc"`
 	ApplyProperties bool     `mapstructure:"applyProperties"`
+	PropertyStyle   string   `mapstructure:"propertyStyle"`
+	When            string   `mapstructure:"when"`
 	Tags            []string `mapstructure:"tags"`
+	Include         []string `mapstructure:"include"`
+	Exclude         []string `mapstructure:"exclude"`
+	Rename          []Rename `mapstructure:"rename"`
+	RenderNames     bool     `mapstructure:"renderNames"` // render template tokens in the names of copied files
+}
+
+// Rename holds a rule that renames files and directories when they are copied
+// from the template into the project
+type Rename struct {
+	From    string `mapstructure:"from"`    // placeholder in the path that is to be replaced
+	To      string `mapstructure:"to"`      // template that is rendered to get the replacement value
+	Package bool   `mapstructure:"package"` // state that the values are Java packages, so dots are directories
 }
 
 type SettingsFramework struct {
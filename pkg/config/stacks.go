@@ -1,10 +1,7 @@
 package config
 
 import (
-	"fmt"
 	"sort"
-
-	"github.com/Ensono/stacks-cli/internal/util"
 )
 
 type Stacks struct {
@@ -27,7 +24,7 @@ func (s *Stacks) GetComponentPackageRef(name string) string {
 	pkg := s.GetComponentPackage(name)
 
 	switch pkg.Type {
-	case "git":
+	case "git", "archive", "oci":
 		result = pkg.URL
 	case "nuget":
 		result = pkg.Name
@@ -84,19 +81,15 @@ func (s *Stacks) GetComponentOptions(framework string) []string {
 
 // Normalize checks to see if the older API is being used and will take
 // the values from that and populate the new structure
-// It will return an error object to be used as a warning for people to update their structure
+// It will return a message to be used as a warning for people to update their structure
+// The type of the package is checked when the downloader for the package is created
 func (p *Package) Normalize() string {
 	var msg string
 
-	// if the type is empty, default to github
+	// if the type is empty, default to git
 	if p.Type == "" {
-		p.Type = "github"
-	}
-
-	// ensure that the type of the repo is correct
-	validTypes := []string{"git", "nuget", "filesystem", "local"}
-	if !util.SliceContains(validTypes, p.Type) {
-		msg = fmt.Sprintf("Specified type of '%s' is invalid, please check your configuration", p.Type)
+		p.Type = "git"
+		msg = "Package type has not been specified, defaulting to 'git'. Please check your configuration"
 	}
 
 	return msg
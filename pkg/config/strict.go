@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// requiredFunc is the name of the function that is added to the actions in a strict
+// template to check that a value has been set
+const requiredFunc = "stacksRequired"
+
+// RenderOptions holds the settings that determine how a template is rendered
+type RenderOptions struct {
+
+	// Delimiters are used to find the actions in the template
+	Delimiters Delimiters
+
+	// Strict states that the template should fail to render if it references a value that
+	// does not exist, or if it outputs a value that has not been set
+	Strict bool
+}
+
+// makeStrict changes every action in the template that outputs a field directly, such as
+// {{ .Input.Business.Company }}, so that it fails if the value is empty. Actions that pass
+// the value through a function, such as {{ .Input.Cloud.Region | default "westeurope" }},
+// are not changed so that optional values can still be used
+func makeStrict(t *template.Template) {
+
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree != nil && tmpl.Tree.Root != nil {
+			strictList(tmpl.Tree.Root)
+		}
+	}
+}
+
+func strictList(list *parse.ListNode) {
+
+	if list == nil {
+		return
+	}
+
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			strictAction(n)
+		case *parse.IfNode:
+			strictList(n.List)
+			strictList(n.ElseList)
+		case *parse.RangeNode:
+			strictList(n.List)
+			strictList(n.ElseList)
+		case *parse.WithNode:
+			strictList(n.List)
+			strictList(n.ElseList)
+		case *parse.ListNode:
+			strictList(n)
+		}
+	}
+}
+
+// strictAction wraps the field in the action with the required function, the path to
+// the field is passed to the function so that it can be reported in the error
+func strictAction(action *parse.ActionNode) {
+
+	pipe := action.Pipe
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return
+	}
+
+	cmd := pipe.Cmds[0]
+	arg := cmd.Args[0]
+
+	switch n := arg.(type) {
+	case *parse.FieldNode, *parse.ChainNode:
+	case *parse.VariableNode:
+		if len(n.Ident) < 2 {
+			return
+		}
+	default:
+		return
+	}
+
+	path := arg.String()
+
+	cmd.Args = []parse.Node{
+		parse.NewIdentifier(requiredFunc).SetPos(arg.Position()),
+		&parse.StringNode{
+			NodeType: parse.NodeString,
+			Pos:      arg.Position(),
+			Quoted:   strconv.Quote(path),
+			Text:     path,
+		},
+		arg,
+	}
+}
+
+// requiredError is returned when a value in a strict template is empty
+type requiredError struct {
+	path  string
+	unset bool
+}
+
+func (e *requiredError) Error() string {
+	if e.unset {
+		return fmt.Sprintf("value for %s has not been set", e.path)
+	}
+
+	return fmt.Sprintf("value for %s is empty", e.path)
+}
+
+// required returns the value, or an error if it has not been set
+func required(path string, value interface{}) (interface{}, error) {
+
+	if value == nil {
+		return nil, &requiredError{path: path, unset: true}
+	}
+
+	if s, ok := value.(string); ok && s == "" {
+		return nil, &requiredError{path: path}
+	}
+
+	return value, nil
+}
+
+// strictError removes the details of the required function from the error, so that the
+// error only contains the location in the template and the path to the empty value
+func strictError(err error) error {
+
+	var reqErr *requiredError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+
+	// the location is at the start of the message, e.g. "template: vars.yml:2:11: executing"
+	msg := err.Error()
+	if idx := strings.Index(msg, " executing "); idx > 0 {
+		return fmt.Errorf("%s %s", msg[:idx], reqErr.Error())
+	}
+
+	return reqErr
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplateStrict(t *testing.T) {
+
+	cfg := Config{}
+	cfg.Input.Business.Company = "my-company"
+
+	replacements := Replacements{}
+	replacements.Input = cfg.Input
+
+	tables := []struct {
+		name     string
+		tmpl     string
+		strict   bool
+		expected string
+		err      string
+	}{
+		{
+			"set value",
+			"company: {{ .Input.Business.Company }}",
+			true,
+			"company: my-company",
+			"",
+		},
+		{
+			"empty value is allowed when not strict",
+			"domain: {{ .Input.Business.Domain }}",
+			false,
+			"domain: ",
+			"",
+		},
+		{
+			"empty value",
+			"company: {{ .Input.Business.Company }}\ndomain: {{ .Input.Business.Domain }}",
+			true,
+			"",
+			"template: vars.yml:2:11: value for .Input.Business.Domain is empty",
+		},
+		{
+			"empty value in a block",
+			"{{ if .Input.Business.Company }}\ndomain: {{ .Input.Business.Domain }}{{ end }}",
+			true,
+			"",
+			"value for .Input.Business.Domain is empty",
+		},
+		{
+			"optional value using a function",
+			`domain: {{ .Input.Business.Domain | default "website" }}`,
+			true,
+			"domain: website",
+			"",
+		},
+		{
+			"missing field",
+			"company: {{ .Input.Bussiness.Company }}",
+			true,
+			"",
+			`template: vars.yml:1:18: executing "vars.yml" at <.Input.Bussiness.Company>: can't evaluate field Bussiness`,
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			rendered, err := cfg.RenderTemplateWithOptions("vars.yml", table.tmpl, replacements, RenderOptions{Strict: table.strict})
+
+			if table.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), table.err)
+				assert.NotContains(t, err.Error(), requiredFunc)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, table.expected, rendered)
+		})
+	}
+}
+
+func TestRenderTemplateVars(t *testing.T) {
+
+	cfg := Config{}
+	cfg.Input.Variables = map[string]string{"team": "platform"}
+
+	replacements := NewReplacements(cfg.Input, Project{Variables: map[string]string{"email": "team@example.com"}})
+
+	rendered, err := cfg.RenderTemplateWithOptions("vars", "{{ .Vars.team }} {{ .Vars.email }}", replacements, RenderOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, "platform team@example.com", rendered)
+
+	// a variable that has not been set is only an error in strict mode
+	rendered, err = cfg.RenderTemplateWithOptions("vars", "{{ .Vars.missing }}", replacements, RenderOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "<no value>", rendered)
+
+	_, err = cfg.RenderTemplateWithOptions("vars", "{{ .Vars.missing }}", replacements, RenderOptions{Strict: true})
+	assert.ErrorContains(t, err, `map has no entry for key "missing"`)
+}
@@ -0,0 +1,343 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	yaml "github.com/goccy/go-yaml"
+)
+
+// templateFuncs returns the functions that are available to templates. Where a function
+// also exists in the Sprig library it has the same name and argument order, so that the
+// value being worked on can be piped into it, e.g. {{ .Project.Name | replace "-" "_" }}
+func templateFuncs(left string, right string) template.FuncMap {
+	return template.FuncMap{
+
+		// string functions
+		"toLower":    strings.ToLower,
+		"toUpper":    strings.ToUpper,
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix string, value string) string { return strings.TrimPrefix(value, prefix) },
+		"trimSuffix": func(suffix string, value string) string { return strings.TrimSuffix(value, suffix) },
+		"replace":    func(old string, new string, value string) string { return strings.ReplaceAll(value, old, new) },
+		"contains":   func(substr string, value string) bool { return strings.Contains(value, substr) },
+		"hasPrefix":  func(prefix string, value string) bool { return strings.HasPrefix(value, prefix) },
+		"hasSuffix":  func(suffix string, value string) bool { return strings.HasSuffix(value, suffix) },
+		"trunc":      trunc,
+		"indent":     indent,
+		"nindent":    func(spaces int, value string) string { return "\n" + indent(spaces, value) },
+		"quote":      func(value interface{}) string { return fmt.Sprintf("%q", toString(value)) },
+
+		// case functions
+		"kebabcase":  kebabCase,
+		"snakecase":  snakeCase,
+		"pascalcase": pascalCase,
+		"camelcase":  camelCase,
+
+		// list functions
+		"join":      join,
+		"split":     split,
+		"splitList": func(sep string, value string) []string { return strings.Split(value, sep) },
+		"list":      func(items ...interface{}) []interface{} { return items },
+
+		// default values
+		"default":  defaultValue,
+		"empty":    isEmpty,
+		"coalesce": coalesce,
+
+		// encoding functions
+		"toJson":    toJSON,
+		"toYaml":    toYAML,
+		"sha256sum": sha256sum,
+		"sha256":    sha256sum,
+		"uuidv4":    uuidv4,
+		"uuid":      uuidv4,
+
+		// environment and time
+		"env":  os.Getenv,
+		"now":  time.Now,
+		"date": func(layout string, date time.Time) string { return date.Format(layout) },
+
+		// cloud resource naming
+		"azureStorageName":  azureStorageName,
+		"azureKeyVaultName": azureKeyVaultName,
+		"azureRegistryName": azureRegistryName,
+		"awsBucketName":     awsBucketName,
+
+		// functions to output text that would otherwise be treated as an action,
+		// e.g. {{ raw "${{ secrets.TOKEN }}" }} or {{ ldelim }}
+		"raw":    func(value string) string { return value },
+		"ldelim": func() string { return left },
+		"rdelim": func() string { return right },
+	}
+}
+
+// words splits the value into words, on any character that is not a letter or a digit
+// and where the case changes, so that "myHTTPServer" becomes "my", "HTTP" and "Server"
+func words(value string) []string {
+
+	var result []string
+	var current []rune
+
+	runes := []rune(value)
+
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := current[len(current)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if !unicode.IsUpper(prev) || nextLower {
+				flush()
+			}
+		}
+
+		current = append(current, r)
+	}
+
+	flush()
+
+	return result
+}
+
+func kebabCase(value string) string {
+	return strings.ToLower(strings.Join(words(value), "-"))
+}
+
+func snakeCase(value string) string {
+	return strings.ToLower(strings.Join(words(value), "_"))
+}
+
+func pascalCase(value string) string {
+
+	var result strings.Builder
+
+	for _, word := range words(value) {
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		result.WriteString(string(runes))
+	}
+
+	return result.String()
+}
+
+// camelCase converts the value to camel case in the same way as Sprig, the first letter of
+// each word is made uppercase and the rest of the word is kept as it is, so that
+// "my-webapi" becomes "MyWebapi" and "myHTTPServer" becomes "MyHTTPServer"
+func camelCase(value string) string {
+
+	var result strings.Builder
+
+	for _, word := range strings.FieldsFunc(value, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		result.WriteString(string(runes))
+	}
+
+	return result.String()
+}
+
+// trunc truncates the value to the length, a negative length keeps the end of the value
+func trunc(length int, value string) string {
+
+	runes := []rune(value)
+
+	if length >= 0 && len(runes) > length {
+		return string(runes[:length])
+	}
+
+	if length < 0 && len(runes) > -length {
+		return string(runes[len(runes)+length:])
+	}
+
+	return value
+}
+
+// indent adds the number of spaces to the start of every line in the value
+func indent(spaces int, value string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(value, "\n", "\n"+pad)
+}
+
+// split splits the value using the separator into a map, in the same way as Sprig, so that
+// each part can be accessed by its index, e.g. {{ (split "," "a,b")._1 }}. Use splitList to
+// get a list of the parts
+func split(sep string, value string) map[string]string {
+
+	parts := strings.Split(value, sep)
+
+	result := make(map[string]string, len(parts))
+	for i, part := range parts {
+		result[fmt.Sprintf("_%d", i)] = part
+	}
+
+	return result
+}
+
+// join joins the items in a list using the separator
+func join(sep string, list interface{}) string {
+
+	if items, ok := list.([]string); ok {
+		return strings.Join(items, sep)
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return toString(list)
+	}
+
+	items := make([]string, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		items[i] = toString(v.Index(i).Interface())
+	}
+
+	return strings.Join(items, sep)
+}
+
+// defaultValue returns the value, unless it is empty in which case the default is returned
+func defaultValue(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || isEmpty(value[0]) {
+		return def
+	}
+
+	return value[0]
+}
+
+// coalesce returns the first value that is not empty
+func coalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !isEmpty(value) {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// isEmpty states if the value is nil or the zero value of its type
+func isEmpty(value interface{}) bool {
+
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
+
+func toString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+func toJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+func toYAML(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	return strings.TrimSuffix(string(data), "\n"), err
+}
+
+func sha256sum(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+// uuidv4 returns a random version 4 UUID
+func uuidv4() (string, error) {
+
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// filterName lowercases the value and removes the characters that are not allowed
+func filterName(value string, allowed func(rune) bool) string {
+	return strings.Map(func(r rune) rune {
+		if allowed(r) {
+			return r
+		}
+		return -1
+	}, strings.ToLower(value))
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
+// azureStorageName returns a name that is valid for an Azure storage account, which can only
+// contain lowercase letters and numbers and be at most 24 characters long
+func azureStorageName(value string) string {
+	return trunc(24, filterName(value, isAlphanumeric))
+}
+
+// azureRegistryName returns a name that is valid for an Azure container registry, which can
+// only contain letters and numbers and be at most 50 characters long
+func azureRegistryName(value string) string {
+	return trunc(50, filterName(value, isAlphanumeric))
+}
+
+// azureKeyVaultName returns a name that is valid for an Azure key vault, which can contain
+// letters, numbers and single hyphens, must start with a letter, must not end with a hyphen
+// and can be at most 24 characters long
+func azureKeyVaultName(value string) string {
+	name := kebabCase(value)
+	name = strings.TrimLeftFunc(name, func(r rune) bool { return r < 'a' || r > 'z' })
+	name = trunc(24, name)
+
+	return strings.TrimRight(name, "-")
+}
+
+// awsBucketName returns a name that is valid for an S3 bucket, which can contain lowercase
+// letters, numbers, hyphens and dots, must start and end with a letter or number and can
+// be at most 63 characters long
+func awsBucketName(value string) string {
+	name := filterName(strings.ReplaceAll(value, "_", "-"), func(r rune) bool {
+		return isAlphanumeric(r) || r == '-' || r == '.'
+	})
+	name = trunc(63, strings.Trim(name, "-."))
+
+	return strings.TrimRight(name, "-.")
+}
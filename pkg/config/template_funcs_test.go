@@ -0,0 +1,66 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+
+	t.Setenv("STACKS_TEMPLATE_TEST", "from-env")
+
+	cfg := Config{}
+	cfg.Input.Business.Company = "Ensono Digital"
+	cfg.Input.Business.Domain = "myHTTPServer"
+
+	replacements := Replacements{}
+	replacements.Input = cfg.Input
+	replacements.Project = Project{Name: "my-webapi"}
+
+	tables := []struct {
+		tmpl     string
+		expected string
+	}{
+		{`{{ .Input.Business.Domain | kebabcase }}`, "my-http-server"},
+		{`{{ .Input.Business.Domain | snakecase }}`, "my_http_server"},
+		{`{{ .Project.Name | pascalcase }}`, "MyWebapi"},
+		{`{{ .Input.Business.Company | camelcase }}`, "EnsonoDigital"},
+		{`{{ .Input.Business.Domain | camelcase }}`, "MyHTTPServer"},
+		{`{{ .Project.Name | camelcase }}`, "MyWebapi"},
+		{`{{ .Project.Name | replace "-" "_" }}`, "my_webapi"},
+		{`{{ .Input.Cloud.Region | default "westeurope" }}`, "westeurope"},
+		{`{{ .Project.Name | default "other" }}`, "my-webapi"},
+		{`{{ splitList "," "a,b,c" | join "-" }}`, "a-b-c"},
+		{`{{ (split "," "a,b,c")._1 }}`, "b"},
+		{`{{ .Project.Name | trunc 2 }}`, "my"},
+		{`{{ .Project.Name | trunc -3 }}`, "api"},
+		{`{{ "abc" | sha256sum }}`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`{{ env "STACKS_TEMPLATE_TEST" }}`, "from-env"},
+		{`{{ "a\nb" | indent 2 }}`, "  a\n  b"},
+		{`name:{{ "a" | nindent 2 }}`, "name:\n  a"},
+		{`{{ list "a" "b" | toJson }}`, `["a","b"]`},
+		{`{{ list "a" "b" | toYaml }}`, "- a\n- b"},
+		{`{{ .Input.Business.Company | azureStorageName }}`, "ensonodigital"},
+		{`{{ "Ensono Digital Production Storage" | azureStorageName }}`, "ensonodigitalproductions"},
+		{`{{ "1-My Key Vault-" | azureKeyVaultName }}`, "my-key-vault"},
+		{`{{ "My_Bucket.Name-" | awsBucketName }}`, "my-bucket.name"},
+	}
+
+	for _, table := range tables {
+		rendered, err := cfg.RenderTemplate("funcs", table.tmpl, replacements)
+		require.NoError(t, err, table.tmpl)
+		assert.Equal(t, table.expected, rendered, table.tmpl)
+	}
+
+	// check the functions that return a value that changes each time
+	rendered, err := cfg.RenderTemplate("uuid", "{{ uuid }}", replacements)
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), rendered)
+
+	rendered, err = cfg.RenderTemplate("now", `{{ now | date "2006" }}`, replacements)
+	require.NoError(t, err)
+	assert.Len(t, rendered, 4)
+}
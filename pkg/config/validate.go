@@ -0,0 +1,332 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// ValidationError is a problem that has been found in a configuration file
+type ValidationError struct {
+	File    string
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", e.File, e.Line, e.Column, e.Path, e.Message)
+}
+
+// ValidateFile reads the file and checks it against the schema
+func ValidateFile(path string, schema *Schema) ([]ValidationError, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file: %s", err.Error())
+	}
+
+	return ValidateYAML(path, data, schema)
+}
+
+// ValidateYAML checks the YAML data against the schema. It reports keys that are not
+// known, values that are the wrong type, pipelines that are not supported and regular
+// expressions that are invalid, along with the line on which they were found. An error
+// is returned if the data cannot be parsed
+func ValidateYAML(file string, data []byte, schema *Schema) ([]ValidationError, error) {
+
+	doc, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", file, err.Error())
+	}
+
+	v := validator{file: file}
+	for _, d := range doc.Docs {
+		if d.Body != nil {
+			v.validate(d.Body, schema, "")
+		}
+	}
+
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		return v.errs[i].Line < v.errs[j].Line
+	})
+
+	return v.errs, nil
+}
+
+type validator struct {
+	file string
+	errs []ValidationError
+}
+
+// addError records the problem at the position of the node
+func (v *validator) addError(node ast.Node, path string, format string, args ...interface{}) {
+
+	e := ValidationError{
+		File:    v.file,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	if tk := node.GetToken(); tk != nil && tk.Position != nil {
+		e.Line = tk.Position.Line
+		e.Column = tk.Position.Column
+	}
+
+	if e.Path == "" {
+		e.Path = "(root)"
+	}
+
+	v.errs = append(v.errs, e)
+}
+
+func (v *validator) validate(node ast.Node, schema *Schema, path string) {
+
+	if schema == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *ast.AnchorNode:
+		v.validate(n.Value, schema, path)
+		return
+	case *ast.TagNode:
+		v.validate(n.Value, schema, path)
+		return
+	case *ast.AliasNode, *ast.NullNode, *ast.CommentGroupNode:
+		return
+	}
+
+	if len(schema.OneOf) > 0 {
+		schema = chooseSchema(node, schema.OneOf)
+	}
+
+	switch schema.Type {
+	case "object":
+		values, ok := mappingValues(node)
+		if !ok {
+			v.addError(node, path, "expected a map but found %s", nodeKind(node))
+			return
+		}
+
+		for _, mv := range values {
+
+			// the keys that are merged in from an anchor are checked where they are defined
+			if _, ok := mv.Key.(*ast.MergeKeyNode); ok {
+				continue
+			}
+
+			key := strings.TrimSpace(mv.Key.String())
+
+			prop, ok := schema.Property(key)
+			if !ok {
+				msg := fmt.Sprintf("unknown key '%s'", key)
+				if suggestion := closestName(key, schema.PropertyNames()); suggestion != "" {
+					msg = fmt.Sprintf("%s, did you mean '%s'?", msg, suggestion)
+				}
+				v.addError(mv.Key, joinPath(path, key), "%s", msg)
+				continue
+			}
+
+			v.validate(mv.Value, prop, joinPath(path, key))
+		}
+
+	case "array":
+		seq, ok := node.(*ast.SequenceNode)
+		if !ok {
+			// a single value is read as a list with one item
+			if isScalar(node) && schema.Items != nil && schema.Items.Type != "object" {
+				v.validate(node, schema.Items, path)
+				return
+			}
+
+			v.addError(node, path, "expected a list but found %s", nodeKind(node))
+			return
+		}
+
+		for i, item := range seq.Values {
+			v.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	case "string", "boolean", "integer", "number":
+		if !isScalar(node) {
+			v.addError(node, path, "expected a %s but found %s", schema.Type, nodeKind(node))
+			return
+		}
+
+		v.validateScalar(node, schema, path)
+	}
+}
+
+// validateScalar checks that the value can be read as the type, strings are accepted for
+// other types if they can be converted, in the same way as the configuration is read
+func (v *validator) validateScalar(node ast.Node, schema *Schema, path string) {
+
+	value := scalarValue(node)
+
+	var err error
+	switch schema.Type {
+	case "boolean":
+		if _, ok := node.(*ast.BoolNode); !ok {
+			_, err = strconv.ParseBool(value)
+		}
+	case "integer":
+		if _, ok := node.(*ast.IntegerNode); !ok {
+			_, err = strconv.Atoi(value)
+		}
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	}
+
+	if err != nil {
+		v.addError(node, path, "value '%s' is not a valid %s", value, schema.Type)
+		return
+	}
+
+	if len(schema.Enum) > 0 && value != "" {
+		found := false
+		for _, allowed := range schema.Enum {
+			if strings.EqualFold(allowed, value) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			v.addError(node, path, "value '%s' is not supported, supported values are %s", value, strings.Join(schema.Enum, ", "))
+		}
+	}
+
+	if schema.Format == "regex" {
+		if _, err := regexp.Compile(value); err != nil {
+			v.addError(node, path, "regular expression '%s' is invalid: %s", value, err.Error())
+		}
+	}
+}
+
+// chooseSchema returns the schema from the list that matches the type of the node
+func chooseSchema(node ast.Node, schemas []*Schema) *Schema {
+
+	want := "string"
+	if _, ok := mappingValues(node); ok {
+		want = "object"
+	} else if _, ok := node.(*ast.SequenceNode); ok {
+		want = "array"
+	}
+
+	for _, schema := range schemas {
+		if schema.Type == want {
+			return schema
+		}
+	}
+
+	return schemas[0]
+}
+
+// mappingValues returns the key/value pairs of a map, a map with a single key is
+// parsed as a value node rather than a mapping node
+func mappingValues(node ast.Node) ([]*ast.MappingValueNode, bool) {
+
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		return n.Values, true
+	case *ast.MappingValueNode:
+		return []*ast.MappingValueNode{n}, true
+	}
+
+	return nil, false
+}
+
+func isScalar(node ast.Node) bool {
+	switch node.(type) {
+	case *ast.StringNode, *ast.LiteralNode, *ast.IntegerNode, *ast.FloatNode,
+		*ast.BoolNode, *ast.InfinityNode, *ast.NanNode:
+		return true
+	}
+
+	return false
+}
+
+// scalarValue returns the value of a scalar node as a string
+func scalarValue(node ast.Node) string {
+	switch n := node.(type) {
+	case *ast.StringNode:
+		return n.Value
+	case *ast.LiteralNode:
+		return n.Value.Value
+	}
+
+	return node.GetToken().Value
+}
+
+// nodeKind describes the type of the node for error messages
+func nodeKind(node ast.Node) string {
+	if _, ok := mappingValues(node); ok {
+		return "a map"
+	}
+	if _, ok := node.(*ast.SequenceNode); ok {
+		return "a list"
+	}
+
+	return fmt.Sprintf("'%s'", scalarValue(node))
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s.%s", path, key)
+}
+
+// closestName returns the name that is the closest to the key, if it is close enough
+// to be a typing mistake
+func closestName(key string, names []string) string {
+
+	sort.Strings(names)
+
+	best := ""
+	bestDistance := 3
+
+	for _, name := range names {
+		distance := levenshtein(strings.ToLower(key), strings.ToLower(name))
+		if distance < bestDistance {
+			best = name
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the number of edits required to change one string into the other
+func levenshtein(a string, b string) int {
+
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchema(t *testing.T) {
+
+	schema, ok := NewSchema(SchemaConfig)
+	require.True(t, ok)
+
+	input, ok := schema.Property("input")
+	require.True(t, ok)
+
+	pipeline, ok := input.Property("pipeline")
+	require.True(t, ok)
+	assert.Equal(t, []string{"azdo", "gha"}, pipeline.Enum)
+
+	_, ok = input.Property("Project")
+	assert.True(t, ok, "properties should be matched without case")
+
+	_, ok = input.Property("unknown")
+	assert.False(t, ok)
+
+	// internal fields and options that are not saved should not be in the schema
+	assert.NotContains(t, input.PropertyNames(), "version")
+	assert.NotContains(t, input.PropertyNames(), "global")
+	assert.NotContains(t, input.PropertyNames(), "folders")
+	assert.NotContains(t, input.Properties["directory"].PropertyNames(), "temp")
+	assert.NotContains(t, input.Properties["directory"].PropertyNames(), "home")
+	assert.Contains(t, input.Properties["directory"].PropertyNames(), "working")
+	assert.NotContains(t, input.Properties["options"].PropertyNames(), "save")
+	assert.Contains(t, input.Properties["options"].PropertyNames(), "dryrun")
+
+	project := input.Properties["project"].Items
+	assert.NotContains(t, project.PropertyNames(), "settings")
+	assert.NotContains(t, project.PropertyNames(), "phases")
+	assert.NotContains(t, project.PropertyNames(), "directory")
+	assert.Contains(t, project.PropertyNames(), "framework")
+
+	profiles, ok := schema.Property("profiles")
+	require.True(t, ok)
+	profile, ok := profiles.Property("clienta")
+	require.True(t, ok)
+	_, ok = profile.Property("input")
+	assert.True(t, ok, "profiles should contain the input configuration")
+
+	data, err := schema.JSON()
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, SchemaDraft, result["$schema"])
+
+	settings, ok := NewSchema(SchemaSettings)
+	require.True(t, ok)
+	assert.Contains(t, settings.PropertyNames(), "pipeline")
+	assert.NotContains(t, settings.Properties["pipeline"].Items.Properties, "logger")
+
+	_, ok = NewSchema("unknown")
+	assert.False(t, ok)
+}
+
+func TestValidateYAMLConfig(t *testing.T) {
+
+	data := `input:
+  pipeline: jenkins
+  busines:
+    company: Ensono
+  log:
+    colour: maybe
+  options:
+    timeout: 10
+  project:
+    - name: myapp
+      framework:
+        type: dotnet
+        properties:
+          auth: true
+      unknown: value
+  variables:
+    anything: goes
+`
+
+	schema, _ := NewSchema(SchemaConfig)
+	errs, err := ValidateYAML("stacks.yml", []byte(data), schema)
+	require.NoError(t, err)
+
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+
+	assert.Equal(t, []string{
+		"stacks.yml:2:13: input.pipeline: value 'jenkins' is not supported, supported values are azdo, gha",
+		"stacks.yml:3:3: input.busines: unknown key 'busines', did you mean 'business'?",
+		"stacks.yml:6:13: input.log.colour: value 'maybe' is not a valid boolean",
+		"stacks.yml:15:7: input.project[0].unknown: unknown key 'unknown'",
+	}, messages)
+}
+
+func TestValidateYAMLSettings(t *testing.T) {
+
+	data := `pipeline:
+  - type: azdo
+    replacements:
+      - pattern: ^(unclosed
+        value: x
+parameters:
+  - name: region
+    regex: "[a-z]+"
+    allowed: westeurope
+init:
+  operations: run
+`
+
+	schema, _ := NewSchema(SchemaSettings)
+	errs, err := ValidateYAML("stackscli.yml", []byte(data), schema)
+	require.NoError(t, err)
+	require.Len(t, errs, 2)
+
+	assert.Equal(t, 4, errs[0].Line)
+	assert.Equal(t, "pipeline[0].replacements[0].pattern", errs[0].Path)
+	assert.Contains(t, errs[0].Message, "regular expression '^(unclosed' is invalid")
+
+	assert.Equal(t, 11, errs[1].Line)
+	assert.Equal(t, "expected a list but found 'run'", errs[1].Message)
+
+	_, err = ValidateYAML("stackscli.yml", []byte("pipeline: {type: azdo\n"), schema)
+	assert.Error(t, err)
+}
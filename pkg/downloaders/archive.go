@@ -0,0 +1,191 @@
+package downloaders
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/models"
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/interfaces"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	Register("archive", func(request PackageRequest) (interfaces.Downloader, error) {
+
+		// check that the URL is valid
+		if _, err := url.ParseRequestURI(request.Package.URL); err != nil {
+			return nil, fmt.Errorf("URL is invalid: %s", err.Error())
+		}
+
+		downloader := NewArchiveDownloader(
+			request.Name,
+			request.Package.URL,
+			request.Package.SHA256,
+			request.Package.StripComponents,
+			request.CacheDir,
+			request.TempDir,
+		)
+		downloader.Subpath = request.Package.Subpath
+
+		return downloader, nil
+	})
+}
+
+// Archive downloads a template that has been published as a zip file or tarball
+// on an HTTP(S) server, such as an artifact server
+type Archive struct {
+	Name            string
+	URL             string
+	SHA256          string
+	StripComponents int
+	CacheDir        string
+	TempDir         string
+	Subpath         string
+
+	// define private properties
+	logger     *logrus.Logger
+	Filesystem billy.Filesystem
+}
+
+func NewArchiveDownloader(name string, url string, sha256 string, stripComponents int, cacheDir string, tempDir string) *Archive {
+	return &Archive{
+		Name:            name,
+		URL:             url,
+		SHA256:          strings.TrimPrefix(strings.ToLower(sha256), "sha256:"),
+		StripComponents: stripComponents,
+		CacheDir:        cacheDir,
+		TempDir:         tempDir,
+	}
+}
+
+func (a *Archive) fs() billy.Filesystem {
+	if a.Filesystem != nil {
+		return a.Filesystem
+	}
+
+	return osfs.New("/")
+}
+
+// Get downloads the archive from the URL, verifies it against the checksum and
+// unpacks it into the TempDir
+func (a *Archive) Get() (string, error) {
+
+	// get the name of the file from the URL, this is used to determine the type of archive
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse archive URL: %s", err.Error())
+	}
+	_, filename := path.Split(u.Path)
+
+	if util.ArchiveType(filename) == "" {
+		return "", fmt.Errorf("unable to determine the type of archive from the URL, supported types are zip, tar, tar.gz and tar.bz2: %s", a.URL)
+	}
+
+	// ensure directories exist
+	// the archives are cached in a directory for the component so that files with the same
+	// name from different servers do not conflict
+	cacheDir := filepath.Join(a.CacheDir, "archives", strings.ToLower(a.Name))
+	if err := a.fs().MkdirAll(cacheDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	if a.TempDir != "" {
+		if err := util.RemoveAll(a.fs(), a.TempDir); err != nil {
+			return "", err
+		}
+		if err := a.fs().MkdirAll(a.TempDir, os.ModePerm); err != nil {
+			return "", err
+		}
+	}
+
+	downloadPath := filepath.Join(cacheDir, filename)
+	err = a.fetchArchive(downloadPath)
+	if err != nil {
+		return "", err
+	}
+
+	// Unpack the archive into the TempDir, ensuring that there is a top level dir to
+	// work with as all projects get unpacked into here
+	unpackDir := filepath.Join(a.TempDir, strings.ToLower(a.Name))
+	if err := a.fs().MkdirAll(unpackDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	dir, err := util.ExtractArchive(downloadPath, unpackDir, a.StripComponents)
+	if err != nil {
+		return "", err
+	}
+
+	return resolveSubpath(dir, a.Subpath)
+}
+
+func (a *Archive) PackageURL() string {
+	return a.URL
+}
+
+func (a *Archive) SetLogger(logger *logrus.Logger) {
+	a.logger = logger
+}
+
+// fetchArchive ensures that the archive exists at the downloadPath and that it matches
+// the checksum. A cached archive is only used if a checksum has been specified and the
+// file matches it
+func (a *Archive) fetchArchive(downloadPath string) error {
+
+	if a.SHA256 == "" {
+		a.logger.Warnf("No checksum has been specified for the archive, it will not be verified: %s", a.URL)
+	} else if util.Exists(downloadPath) {
+		err := a.verifyArchive(downloadPath)
+		if err == nil {
+			a.logger.Infof("Using archive from local cache: %s", downloadPath)
+			return nil
+		}
+
+		a.logger.Warnf("Cached archive is invalid and will be downloaded again: %s", err.Error())
+	}
+
+	a.logger.Infof("Downloading archive: %s", a.URL)
+	ac := models.NewAPICall(a.URL, "")
+	err := ac.Download(downloadPath)
+	if err != nil {
+		_ = os.Remove(downloadPath)
+		return err
+	}
+
+	if a.SHA256 == "" {
+		return nil
+	}
+
+	// check the archive that has been downloaded, removing it if it is not valid
+	// so that it is not used on the next run
+	err = a.verifyArchive(downloadPath)
+	if err != nil {
+		_ = os.Remove(downloadPath)
+		return fmt.Errorf("archive does not match the specified checksum: %s", err.Error())
+	}
+
+	return nil
+}
+
+// verifyArchive checks the SHA256 checksum of the file at the specified path
+func (a *Archive) verifyArchive(path string) error {
+
+	sum, err := util.FileChecksum(path, "sha256")
+	if err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(sum)
+	if actual != a.SHA256 {
+		return fmt.Errorf("SHA256 mismatch for '%s', expected '%s' but found '%s'", filepath.Base(path), a.SHA256, actual)
+	}
+
+	return nil
+}
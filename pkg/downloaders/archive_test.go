@@ -0,0 +1,157 @@
+package downloaders
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTemplateTarball returns a gzip compressed tarball containing a template in
+// a top level directory, as published on an artifact server
+func createTemplateTarball(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	files := map[string]string{
+		"stacks-template-1.0.0/stackscli.yml": "framework:\n  name: infra\n",
+		"stacks-template-1.0.0/README.md":     "# Template",
+	}
+
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func TestNewArchiveDownloader(t *testing.T) {
+	downloader := NewArchiveDownloader("infra_template", "https://artifacts.example.com/template.tar.gz", "SHA256:ABC123", 1, "/tmp/cache", "/tmp/archive")
+
+	assert.Equal(t, "infra_template", downloader.Name, "Name should be set correctly")
+	assert.Equal(t, "https://artifacts.example.com/template.tar.gz", downloader.URL, "URL should be set correctly")
+	assert.Equal(t, "abc123", downloader.SHA256, "Checksum should be normalised")
+	assert.Equal(t, 1, downloader.StripComponents, "StripComponents should be set correctly")
+	assert.Equal(t, "https://artifacts.example.com/template.tar.gz", downloader.PackageURL(), "PackageURL should return the URL")
+}
+
+func TestArchive_Get(t *testing.T) {
+
+	tarball := createTemplateTarball(t)
+	sum := sha256.Sum256(tarball)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(tarball)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name     string
+		checksum string
+		strip    int
+		wantErr  string
+		wantDir  string
+	}{
+		{
+			name:     "Valid checksum",
+			checksum: hex.EncodeToString(sum[:]),
+			wantDir:  "stacks-template-1.0.0",
+		},
+		{
+			name:     "Valid checksum with stripped components",
+			checksum: hex.EncodeToString(sum[:]),
+			strip:    1,
+		},
+		{
+			name:    "No checksum",
+			strip:   1,
+			wantDir: "",
+		},
+		{
+			name:     "Invalid checksum",
+			checksum: "0000",
+			wantErr:  "archive does not match the specified checksum",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheDir := t.TempDir()
+			tempDir := filepath.Join(t.TempDir(), "temp")
+
+			downloader := NewArchiveDownloader("infra_template", server.URL+"/releases/template.tar.gz", tt.checksum, tt.strip, cacheDir, tempDir)
+			downloader.SetLogger(logger)
+
+			dir, err := downloader.Get()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(tempDir, "infra_template", tt.wantDir), dir, "Template directory should be returned")
+			assert.FileExists(t, filepath.Join(dir, "stackscli.yml"))
+		})
+	}
+
+	// the archive with a checksum is cached, so a subsequent request should not download it
+	cacheDir := t.TempDir()
+	downloader := NewArchiveDownloader("infra_template", server.URL+"/releases/template.tar.gz", hex.EncodeToString(sum[:]), 1, cacheDir, t.TempDir())
+	downloader.SetLogger(logger)
+
+	requests = 0
+	_, err := downloader.Get()
+	require.NoError(t, err)
+	_, err = downloader.Get()
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests, "Cached archive should be used when the checksum matches")
+}
+
+func TestArchive_Get_UnsupportedType(t *testing.T) {
+	downloader := NewArchiveDownloader("infra_template", "https://artifacts.example.com/template.rar", "", 0, t.TempDir(), t.TempDir())
+	downloader.SetLogger(logrus.New())
+
+	_, err := downloader.Get()
+	assert.ErrorContains(t, err, "unable to determine the type of archive")
+}
+
+func TestArchive_Get_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	cacheDir := t.TempDir()
+	downloader := NewArchiveDownloader("infra_template", server.URL+"/template.zip", "", 0, cacheDir, t.TempDir())
+	downloader.SetLogger(logger)
+
+	_, err := downloader.Get()
+	assert.ErrorContains(t, err, "404")
+
+	_, statErr := os.Stat(filepath.Join(cacheDir, "archives", "infra_template", "template.zip"))
+	assert.True(t, os.IsNotExist(statErr), "Failed download should be removed from the cache")
+}
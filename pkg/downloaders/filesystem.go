@@ -8,6 +8,7 @@ import (
 	cp "github.com/otiai10/copy"
 
 	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/interfaces"
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/sirupsen/logrus"
@@ -15,9 +16,22 @@ import (
 
 var filesystem = &Filesystem{}
 
+func init() {
+	factory := func(request PackageRequest) (interfaces.Downloader, error) {
+		downloader := NewFilesystemDownloader(request.Package.Path, request.TempDir)
+		downloader.Subpath = request.Package.Subpath
+
+		return downloader, nil
+	}
+
+	Register("filesystem", factory)
+	Register("local", factory)
+}
+
 type Filesystem struct {
 	Path    string
 	TempDir string
+	Subpath string
 
 	// define private properties
 	logger     *logrus.Logger
@@ -72,9 +86,11 @@ func (f *Filesystem) Get() (string, error) {
 		if f.logger != nil {
 			f.logger.Errorf("Issue copying files: %s", err.Error())
 		}
+
+		return f.TempDir, err
 	}
 
-	return f.TempDir, err
+	return resolveSubpath(f.TempDir, f.Subpath)
 }
 
 func (f *Filesystem) SetLogger(logger *logrus.Logger) {
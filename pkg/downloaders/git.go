@@ -1,10 +1,13 @@
 package downloaders
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
 	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/interfaces"
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/sirupsen/logrus"
@@ -12,12 +15,36 @@ import (
 
 var git = &Git{}
 
+func init() {
+	Register("git", func(request PackageRequest) (interfaces.Downloader, error) {
+
+		// check that the URL is valid
+		if _, err := url.ParseRequestURI(request.Package.URL); err != nil {
+			return nil, fmt.Errorf("URL is invalid: %s", err.Error())
+		}
+
+		downloader := NewGitDownloader(
+			request.Package.URL,
+			request.Package.Version,
+			request.FrameworkVersion,
+			request.TempDir,
+			request.Token,
+		)
+		downloader.Subpath = request.Package.Subpath
+		downloader.GitHubAPI = request.GitHubAPI
+
+		return downloader, nil
+	})
+}
+
 type Git struct {
 	URL              string
 	Version          string
 	FrameworkVersion string
 	TempDir          string
 	Token            string
+	Subpath          string
+	GitHubAPI        string
 
 	logger     *logrus.Logger
 	Filesystem billy.Filesystem
@@ -64,13 +91,17 @@ func (g *Git) Get() (string, error) {
 	// call the GitClone method
 	dir, err = util.GitClone(
 		g.URL,
+		g.GitHubAPI,
 		g.FrameworkVersion,
 		g.Version,
 		tempDir,
 		g.Token,
 	)
+	if err != nil {
+		return dir, err
+	}
 
-	return dir, err
+	return resolveSubpath(dir, g.Subpath)
 
 }
 
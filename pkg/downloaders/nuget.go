@@ -1,6 +1,8 @@
 package downloaders
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -11,11 +13,28 @@ import (
 
 	"github.com/Ensono/stacks-cli/internal/models"
 	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/interfaces"
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/sirupsen/logrus"
 )
 
+func init() {
+	Register("nuget", func(request PackageRequest) (interfaces.Downloader, error) {
+		downloader := NewNugetDownloader(
+			request.Package.Name,
+			request.Package.ID,
+			request.FrameworkVersion,
+			request.CacheDir,
+			request.TempDir,
+		)
+		downloader.Subpath = request.Package.Subpath
+		downloader.NoVerify = request.NoVerify
+
+		return downloader, nil
+	})
+}
+
 type Nuget struct {
 	Name             string
 	ID               string
@@ -23,6 +42,10 @@ type Nuget struct {
 	FrameworkVersion string
 	TempDir          string
 	CacheDir         string
+	Subpath          string
+
+	// NoVerify allows a package to be used when its hash cannot be retrieved from the catalog
+	NoVerify bool
 
 	// define private properties
 	url        string
@@ -32,23 +55,27 @@ type Nuget struct {
 }
 
 type NugetResponse struct {
-	Items          []NugetResponseItem "json:`items`"
-	PackageContent string              "json:`packageContent`"
+	Items          []NugetResponseItem `json:"items"`
+	PackageContent string              `json:"packageContent"`
+	CatalogEntry   string              `json:"catalogEntry"`
 }
 
 type NugetResponseItem struct {
-	Count int                      "json:`count`"
-	Items []NugetResponsePageItems "json:`items`"
+	Count int                      `json:"count"`
+	Items []NugetResponsePageItems `json:"items"`
 }
 
 type NugetResponsePageItems struct {
-	CatalogEntry NugetItemCatalogEntry "json:`catalogEntry`"
+	CatalogEntry NugetItemCatalogEntry `json:"catalogEntry"`
 }
 
 type NugetItemCatalogEntry struct {
-	ID             string "json:`id`"
-	PackageContent string "json:`packageContent`"
-	Version        string "json:`version`"
+	URL                  string `json:"@id"`
+	ID                   string `json:"id"`
+	PackageContent       string `json:"packageContent"`
+	PackageHash          string `json:"packageHash"`
+	PackageHashAlgorithm string `json:"packageHashAlgorithm"`
+	Version              string `json:"version"`
 }
 
 func NewNugetDownloader(name string, id string, version string, cacheDir string, tempDir string) *Nuget {
@@ -134,7 +161,18 @@ func (n *Nuget) Get() (string, error) {
 
 	uri, _ := n.getPackageURL(ac)
 	n.url = uri
-	ac.UpdateURL(uri)
+
+	// get the catalog entry for the package so that the hash of the package is known
+	entry, err := n.getCatalogEntry(ac)
+	if err == nil && entry.PackageHash == "" {
+		err = fmt.Errorf("catalog entry does not contain a package hash")
+	}
+	if err != nil {
+		if !n.NoVerify {
+			return "", fmt.Errorf("unable to retrieve package hash, use --noverify to use the package without verifying it: %s", err.Error())
+		}
+		n.logger.Warnf("Unable to retrieve package hash, package will not be verified: %s", err.Error())
+	}
 
 	// get the name of the file from the URL
 	u, _ := url.Parse(uri)
@@ -143,15 +181,11 @@ func (n *Nuget) Get() (string, error) {
 	// determine the path for the downloaded file, this will go into the cachedir
 	downloadPath := filepath.Join(n.CacheDir, filename)
 
-	// if the file does not exist download it
-	if util.Exists(downloadPath) {
-		n.logger.Infof("Using package from local cache: %s", downloadPath)
-	} else {
-		n.logger.Info("Downloading package from Nuget")
-		err = ac.Download(downloadPath)
-		if err != nil {
-			return dir, err
-		}
+	// ensure that the package exists in the cache and that it matches the hash from the catalog
+	ac.UpdateURL(uri)
+	err = n.fetchPackage(ac, downloadPath, entry)
+	if err != nil {
+		return dir, err
 	}
 
 	// Unpack the Nuget package into the TempDir
@@ -167,11 +201,96 @@ func (n *Nuget) Get() (string, error) {
 	}
 
 	// The package will unpack into a different folder structure than if had been retrieved
-	// from github. This nested path needs to be set on the returned dir so that the CLI
-	// can find the settings file for the project
-	templateDir := filepath.Join(unpackDir, "content", "templates", n.ID)
+	// from github. Unless a subpath has been specified, the nested path needs to be set on
+	// the returned dir so that the CLI can find the settings file for the project
+	subpath := n.Subpath
+	if subpath == "" {
+		subpath = path.Join("content", "templates", n.ID)
+	}
+
+	return resolveSubpath(unpackDir, subpath)
+}
+
+// fetchPackage ensures that the package exists at the downloadPath and that it matches
+// the hash that has been published in the catalog entry. A cached package that does not
+// match is downloaded again, as is a download that does not match. If the package still
+// does not match the hash after being downloaded again an error is returned
+func (n *Nuget) fetchPackage(ac *models.APICall, downloadPath string, entry NugetItemCatalogEntry) error {
+
+	// if the file exists in the cache, check that it is valid before using it
+	if util.Exists(downloadPath) {
+		err := n.verifyPackage(downloadPath, entry)
+		if err == nil {
+			n.logger.Infof("Using package from local cache: %s", downloadPath)
+			return nil
+		}
+
+		n.logger.Warnf("Cached package is invalid and will be downloaded again: %s", err.Error())
+	}
+
+	// attempt the download twice, this is so that a download that has been interrupted
+	// is retried before failing
+	var err error
+	for attempt := 1; attempt <= 2; attempt++ {
+		n.logger.Info("Downloading package from Nuget")
+		err = ac.Download(downloadPath)
+		if err != nil {
+			return err
+		}
+
+		err = n.verifyPackage(downloadPath, entry)
+		if err == nil {
+			return nil
+		}
+
+		n.logger.Warnf("Downloaded package is invalid: %s", err.Error())
+	}
+
+	// remove the invalid file so that it is not used on the next run
+	_ = os.Remove(downloadPath)
+
+	return fmt.Errorf("package '%s' does not match the hash published in the Nuget catalog: %s", n.Name, err.Error())
+}
 
-	return templateDir, err
+// verifyPackage checks the hash of the file at the specified path against the hash in the
+// catalog entry. If the catalog entry does not have a hash then the package cannot be verified
+// and an error is returned, unless verification has been turned off
+func (n *Nuget) verifyPackage(path string, entry NugetItemCatalogEntry) error {
+
+	if entry.PackageHash == "" {
+		if !n.NoVerify {
+			return fmt.Errorf("no package hash available, unable to verify package: %s", path)
+		}
+		n.logger.Warnf("No package hash available, unable to verify package: %s", path)
+		return nil
+	}
+
+	// the hash in the Nuget catalog is base64 encoded
+	expected, err := base64.StdEncoding.DecodeString(entry.PackageHash)
+	if err != nil {
+		return fmt.Errorf("unable to decode package hash: %s", err.Error())
+	}
+
+	algorithm := entry.PackageHashAlgorithm
+	if algorithm == "" {
+		algorithm = "SHA512"
+	}
+
+	actual, err := util.FileChecksum(path, algorithm)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(expected, actual) {
+		return fmt.Errorf("%s hash mismatch for '%s', expected '%s' but found '%s'",
+			algorithm,
+			filepath.Base(path),
+			entry.PackageHash,
+			base64.StdEncoding.EncodeToString(actual),
+		)
+	}
+
+	return nil
 }
 
 func (n *Nuget) PackageURL() string {
@@ -218,6 +337,47 @@ func (n *Nuget) getPackageURL(ac *models.APICall) (string, error) {
 	return url, err
 }
 
+// getCatalogEntry retrieves the catalog entry for the package, which holds the hash and
+// the hash algorithm of the package. The location of the catalog entry is in the registration
+// response that has already been retrieved
+func (n *Nuget) getCatalogEntry(ac *models.APICall) (NugetItemCatalogEntry, error) {
+
+	var entry NugetItemCatalogEntry
+
+	var resp NugetResponse
+	err := json.Unmarshal(ac.Raw(), &resp)
+	if err != nil {
+		return entry, fmt.Errorf("issue reading response body from Nuget: %s", err.Error())
+	}
+
+	// determine the URL of the catalog entry based on the type of response
+	catalogURL := resp.CatalogEntry
+	if n.latest && len(resp.Items) > 0 && len(resp.Items[0].Items) > 0 {
+		catalogURL = resp.Items[0].Items[len(resp.Items[0].Items)-1].CatalogEntry.URL
+	}
+
+	if catalogURL == "" {
+		return entry, fmt.Errorf("catalog entry not found for package")
+	}
+
+	ac.UpdateURL(catalogURL)
+	err, statusCode := ac.Do("GET")
+	if err != nil {
+		return entry, fmt.Errorf("problem calling the Nuget api: %s", err.Error())
+	}
+
+	if statusCode > 299 {
+		return entry, fmt.Errorf("error retrieving catalog entry: %d", statusCode)
+	}
+
+	err = json.Unmarshal(ac.Raw(), &entry)
+	if err != nil {
+		return entry, fmt.Errorf("issue reading catalog entry from Nuget: %s", err.Error())
+	}
+
+	return entry, nil
+}
+
 func (n *Nuget) getLatestVersion(ac *models.APICall) error {
 
 	var err error
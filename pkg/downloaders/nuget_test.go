@@ -1,9 +1,16 @@
 package downloaders
 
 import (
+	"crypto/sha512"
+	"encoding/base64"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/Ensono/stacks-cli/internal/models"
+	"github.com/Ensono/stacks-cli/internal/util"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -542,3 +549,120 @@ func TestNuget_CacheScenarios(t *testing.T) {
 	assert.NotNil(t, downloader, "Downloader should be created for cache testing")
 	t.Skip("Cache testing requires mocking util.Exists() and filesystem operations")
 }
+
+// nugetHash returns the base64 encoded SHA512 hash of the data, as found in the Nuget catalog
+func nugetHash(data []byte) string {
+	sum := sha512.Sum512(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestNuget_VerifyPackage(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "testpackage.1.0.0.nupkg")
+	err := os.WriteFile(path, []byte("package content"), 0644)
+	assert.Nil(t, err)
+
+	downloader := NewNugetDownloader("testpackage", "package-id", "1.0.0", "/tmp/cache", "/tmp")
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	downloader.SetLogger(logger)
+
+	testCases := []struct {
+		name     string
+		entry    NugetItemCatalogEntry
+		noVerify bool
+		isValid  bool
+	}{
+		{
+			name:    "Matching hash",
+			entry:   NugetItemCatalogEntry{PackageHash: nugetHash([]byte("package content")), PackageHashAlgorithm: "SHA512"},
+			isValid: true,
+		},
+		{
+			name:    "Mismatched hash",
+			entry:   NugetItemCatalogEntry{PackageHash: nugetHash([]byte("truncated")), PackageHashAlgorithm: "SHA512"},
+			isValid: false,
+		},
+		{
+			name:    "Default algorithm",
+			entry:   NugetItemCatalogEntry{PackageHash: nugetHash([]byte("package content"))},
+			isValid: true,
+		},
+		{
+			name:    "No hash in catalog",
+			entry:   NugetItemCatalogEntry{},
+			isValid: false,
+		},
+		{
+			name:     "No hash in catalog with verification turned off",
+			entry:    NugetItemCatalogEntry{},
+			noVerify: true,
+			isValid:  true,
+		},
+		{
+			name:     "Mismatched hash with verification turned off",
+			entry:    NugetItemCatalogEntry{PackageHash: nugetHash([]byte("truncated")), PackageHashAlgorithm: "SHA512"},
+			noVerify: true,
+			isValid:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			downloader.NoVerify = tc.noVerify
+			err := downloader.verifyPackage(path, tc.entry)
+			assert.Equal(t, tc.isValid, err == nil, "Unexpected verification result: %v", err)
+		})
+	}
+}
+
+func TestNuget_FetchPackage_ReplacesInvalidCache(t *testing.T) {
+
+	content := []byte("complete package")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	// write a truncated package into the cache
+	downloadPath := filepath.Join(t.TempDir(), "testpackage.1.0.0.nupkg")
+	err := os.WriteFile(downloadPath, content[:4], 0644)
+	assert.Nil(t, err)
+
+	downloader := NewNugetDownloader("testpackage", "package-id", "1.0.0", "/tmp/cache", "/tmp")
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	downloader.SetLogger(logger)
+
+	entry := NugetItemCatalogEntry{PackageHash: nugetHash(content), PackageHashAlgorithm: "SHA512"}
+	err = downloader.fetchPackage(models.NewAPICall(server.URL, ""), downloadPath, entry)
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(downloadPath)
+	assert.Nil(t, err)
+	assert.Equal(t, content, data, "Cached package should have been downloaded again")
+}
+
+func TestNuget_FetchPackage_HashMismatch(t *testing.T) {
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("corrupt package"))
+	}))
+	defer server.Close()
+
+	downloadPath := filepath.Join(t.TempDir(), "testpackage.1.0.0.nupkg")
+
+	downloader := NewNugetDownloader("testpackage", "package-id", "1.0.0", "/tmp/cache", "/tmp")
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	downloader.SetLogger(logger)
+
+	entry := NugetItemCatalogEntry{PackageHash: nugetHash([]byte("complete package")), PackageHashAlgorithm: "SHA512"}
+	err := downloader.fetchPackage(models.NewAPICall(server.URL, ""), downloadPath, entry)
+
+	assert.ErrorContains(t, err, "does not match the hash published in the Nuget catalog")
+	assert.Equal(t, 2, requests, "Package should be downloaded again before failing")
+	assert.False(t, util.Exists(downloadPath), "Invalid package should be removed from the cache")
+}
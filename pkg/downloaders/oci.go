@@ -0,0 +1,536 @@
+package downloaders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/httpclient"
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/Ensono/stacks-cli/pkg/interfaces"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/sirupsen/logrus"
+)
+
+func init() {
+	Register("oci", func(request PackageRequest) (interfaces.Downloader, error) {
+		downloader := NewOCIDownloader(
+			request.Name,
+			request.Package.URL,
+			request.Package.Version,
+			request.Package.StripComponents,
+			request.CacheDir,
+			request.TempDir,
+		)
+		downloader.Subpath = request.Package.Subpath
+		downloader.Registries = request.Registries
+
+		return downloader, nil
+	})
+}
+
+// ociDigestHash matches the hash of a sha256 digest
+var ociDigestHash = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// media types that are requested when retrieving the manifest for an artifact
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// OCI downloads a template that has been published as an artifact in an OCI registry
+type OCI struct {
+	Name            string
+	Reference       string
+	Version         string
+	StripComponents int
+	CacheDir        string
+	TempDir         string
+	Subpath         string
+
+	// Registries holds the credentials for registries that require authentication
+	Registries []config.Registry
+
+	// define private properties
+	scheme     string
+	registry   string
+	repository string
+	tag        string
+	bearer     string
+	client     *httpclient.Client
+	logger     *logrus.Logger
+	Filesystem billy.Filesystem
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+type ociToken struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// NewOCIDownloader creates a downloader for the artifact at the reference, which is in the
+// format `registry/repository:tag` or `registry/repository@digest`. If the reference does not
+// contain a tag or digest then the version is used as the tag
+func NewOCIDownloader(name string, reference string, version string, stripComponents int, cacheDir string, tempDir string) *OCI {
+	return &OCI{
+		Name:            name,
+		Reference:       reference,
+		Version:         version,
+		StripComponents: stripComponents,
+		CacheDir:        cacheDir,
+		TempDir:         tempDir,
+		client:          httpclient.Default(),
+	}
+}
+
+func (o *OCI) fs() billy.Filesystem {
+	if o.Filesystem != nil {
+		return o.Filesystem
+	}
+
+	return osfs.New("/")
+}
+
+// Get pulls the manifest for the artifact from the registry and extracts each of the
+// layers into the TempDir
+func (o *OCI) Get() (string, error) {
+
+	err := o.parseReference()
+	if err != nil {
+		return "", err
+	}
+
+	o.logger.Infof("Pulling artifact: %s/%s:%s", o.registry, o.repository, o.tag)
+
+	// ensure directories exist
+	blobDir := filepath.Join(o.CacheDir, "oci", "blobs", "sha256")
+	if err := o.fs().MkdirAll(blobDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	if o.TempDir != "" {
+		if err := util.RemoveAll(o.fs(), o.TempDir); err != nil {
+			return "", err
+		}
+		if err := o.fs().MkdirAll(o.TempDir, os.ModePerm); err != nil {
+			return "", err
+		}
+	}
+
+	manifest, err := o.getManifest(o.tag)
+	if err != nil {
+		return "", err
+	}
+
+	if len(manifest.Layers) == 0 {
+		return "", fmt.Errorf("artifact does not contain any layers: %s", o.Reference)
+	}
+
+	// Unpack the layers into the TempDir, ensuring that there is a top level dir to
+	// work with as all projects get unpacked into here
+	unpackDir := filepath.Join(o.TempDir, strings.ToLower(o.Name))
+	if err := o.fs().MkdirAll(unpackDir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	for _, layer := range manifest.Layers {
+
+		blobPath, err := o.getBlob(layer, blobDir)
+		if err != nil {
+			return "", err
+		}
+
+		err = o.extractLayer(layer, blobPath, unpackDir)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return resolveSubpath(unpackDir, o.Subpath)
+}
+
+func (o *OCI) PackageURL() string {
+	return o.Reference
+}
+
+func (o *OCI) SetLogger(logger *logrus.Logger) {
+	o.logger = logger
+}
+
+// parseReference splits the reference into the registry, repository and the tag or digest.
+// The scheme defaults to https, but `http://` can be used for local registries
+func (o *OCI) parseReference() error {
+
+	ref := o.Reference
+	o.scheme = "https"
+
+	for prefix, scheme := range map[string]string{"oci://": "https", "https://": "https", "http://": "http"} {
+		if strings.HasPrefix(ref, prefix) {
+			ref = strings.TrimPrefix(ref, prefix)
+			o.scheme = scheme
+			break
+		}
+	}
+
+	// the registry is everything up to the first slash
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("OCI reference must be in the format registry/repository:tag or registry/repository@digest: %s", o.Reference)
+	}
+	o.registry = parts[0]
+	remainder := parts[1]
+
+	// determine if a digest or tag has been set, the tag can only be after the last slash
+	// as the colon may be part of the registry port
+	if at := strings.Index(remainder, "@"); at >= 0 {
+		o.repository = remainder[:at]
+		o.tag = remainder[at+1:]
+	} else if colon := strings.LastIndex(remainder, ":"); colon > strings.LastIndex(remainder, "/") {
+		o.repository = remainder[:colon]
+		o.tag = remainder[colon+1:]
+	} else {
+		o.repository = remainder
+		o.tag = o.Version
+	}
+
+	if o.tag == "" {
+		o.tag = "latest"
+	}
+
+	return nil
+}
+
+// getManifest retrieves the manifest for the reference. If the reference is an index
+// then the first manifest in the index is retrieved
+func (o *OCI) getManifest(reference string) (ociManifest, error) {
+
+	var manifest ociManifest
+
+	endpoint := fmt.Sprintf("%s://%s/v2/%s/manifests/%s", o.scheme, o.registry, o.repository, reference)
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return manifest, fmt.Errorf("unable to create HTTP request for '%s': %s", endpoint, err.Error())
+	}
+	req.Header.Set("Accept", strings.Join(ociManifestMediaTypes, ", "))
+
+	resp, err := o.do(req)
+	if err != nil {
+		return manifest, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return manifest, fmt.Errorf("error retrieving manifest for '%s': %d", o.Reference, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return manifest, err
+	}
+
+	// if the manifest has been requested by digest, ensure that it matches
+	if strings.HasPrefix(reference, "sha256:") {
+		sum := sha256.Sum256(body)
+		if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != reference {
+			return manifest, fmt.Errorf("manifest digest mismatch, expected '%s' but found '%s'", reference, actual)
+		}
+	}
+
+	err = json.Unmarshal(body, &manifest)
+	if err != nil {
+		return manifest, fmt.Errorf("unable to read manifest: %s", err.Error())
+	}
+
+	// if this is an index, get the first manifest that it refers to
+	if len(manifest.Manifests) > 0 && len(manifest.Layers) == 0 {
+		if _, err := parseDigest(manifest.Manifests[0].Digest); err != nil {
+			return manifest, err
+		}
+		return o.getManifest(manifest.Manifests[0].Digest)
+	}
+
+	return manifest, nil
+}
+
+// getBlob downloads the blob for the layer into the blob directory, if it does not already
+// exist, and verifies it against the digest. Returns the path to the blob
+func (o *OCI) getBlob(layer ociDescriptor, blobDir string) (string, error) {
+
+	hash, err := parseDigest(layer.Digest)
+	if err != nil {
+		return "", err
+	}
+
+	// blobs are content addressable so if it exists and is valid it can be used
+	blobPath := filepath.Join(blobDir, hash)
+	if util.Exists(blobPath) {
+		if err := verifyBlob(blobPath, hash); err == nil {
+			o.logger.Debugf("Using layer from local cache: %s", layer.Digest)
+			return blobPath, nil
+		}
+	}
+
+	endpoint := fmt.Sprintf("%s://%s/v2/%s/blobs/%s", o.scheme, o.registry, o.repository, layer.Digest)
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to create HTTP request for '%s': %s", endpoint, err.Error())
+	}
+
+	resp, err := o.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return "", fmt.Errorf("error downloading layer '%s': %d", layer.Digest, resp.StatusCode)
+	}
+
+	if err = o.client.SaveResponse(resp, blobPath); err != nil {
+		return "", err
+	}
+
+	if err = verifyBlob(blobPath, hash); err != nil {
+		_ = os.Remove(blobPath)
+		return "", err
+	}
+
+	return blobPath, nil
+}
+
+// extractLayer unpacks the layer into the unpackDir. Layers that are tarballs are extracted,
+// all other layers are written out using the title annotation as the filename
+func (o *OCI) extractLayer(layer ociDescriptor, blobPath string, unpackDir string) error {
+
+	var archiveType string
+
+	switch {
+	case strings.HasSuffix(layer.MediaType, "tar+gzip"), strings.HasSuffix(layer.MediaType, "tar.gzip"):
+		archiveType = "tar.gz"
+	case strings.HasSuffix(layer.MediaType, ".tar"), strings.HasSuffix(layer.MediaType, "+tar"):
+		archiveType = "tar"
+	}
+
+	if archiveType != "" {
+		_, err := util.ExtractArchiveType(blobPath, unpackDir, archiveType, o.StripComponents)
+		return err
+	}
+
+	title := layer.Annotations["org.opencontainers.image.title"]
+	if title == "" {
+		o.logger.Warnf("Skipping layer with unsupported media type and no title: %s", layer.Digest)
+		return nil
+	}
+
+	filename, err := layerFilename(title)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(blobPath)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(unpackDir, filename), content, 0644)
+}
+
+// layerFilename returns the name of the file that a layer is written to from its title.
+// Only the last part of the title is used and names that would not be written into the
+// directory, such as `..` or `/`, are rejected
+func layerFilename(title string) (string, error) {
+
+	filename := filepath.Base(filepath.FromSlash(title))
+
+	if filename == "." || filename == ".." || strings.ContainsAny(filename, `/\`) || filepath.VolumeName(filename) != "" {
+		return "", fmt.Errorf("layer title is not a valid file name: %s", title)
+	}
+
+	return filename, nil
+}
+
+// do performs the request against the registry. If the registry responds with a bearer
+// challenge then a token is requested and the request is retried with the token
+func (o *OCI) do(req *http.Request) (*http.Response, error) {
+
+	if o.bearer != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", o.bearer))
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to access registry:\n\tURL: %s\n\t%s", req.URL.String(), err.Error())
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized || o.bearer != "" {
+		return resp, nil
+	}
+
+	// get a token using the challenge from the registry
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	o.bearer, err = o.authenticate(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.do(req)
+}
+
+// authenticate requests a bearer token from the realm in the challenge. If credentials have
+// been configured for the registry they are used, otherwise an anonymous token is requested
+func (o *OCI) authenticate(challenge string) (string, error) {
+
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", fmt.Errorf("registry requires unsupported authentication: %s", challenge)
+	}
+
+	// get the parameters from the challenge
+	params := make(map[string]string)
+	re := regexp.MustCompile(`(\w+)="([^"]*)"`)
+	for _, match := range re.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry authentication challenge does not contain a realm")
+	}
+
+	if params["scope"] == "" {
+		params["scope"] = fmt.Sprintf("repository:%s:pull", o.repository)
+	}
+
+	query := url.Values{}
+	query.Set("scope", params["scope"])
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("registry authentication realm is not valid: %s", err.Error())
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	username, password, err := o.credentials(realm)
+	if err != nil {
+		return "", err
+	}
+	if password != "" {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to authenticate with registry: %s", err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return "", fmt.Errorf("unable to authenticate with registry: %d", resp.StatusCode)
+	}
+
+	var token ociToken
+	err = json.NewDecoder(resp.Body).Decode(&token)
+	if err != nil {
+		return "", fmt.Errorf("unable to read registry token: %s", err.Error())
+	}
+
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	return token.Token, nil
+}
+
+// credentials returns the username and password that have been configured for the registry.
+// They are only returned if the realm is HTTPS and on the registry host, or the auth host
+// that has been configured for it, so that they cannot be sent to any other host
+func (o *OCI) credentials(realm *url.URL) (string, string, error) {
+
+	var registry config.Registry
+	var found bool
+	for _, item := range o.Registries {
+		if strings.EqualFold(item.Host, o.registry) {
+			registry, found = item, true
+			break
+		}
+	}
+
+	if !found {
+		return "", "", nil
+	}
+
+	if realm.Scheme != "https" || !registry.AllowsAuthHost(realm.Host) {
+		o.logger.Warnf("Requesting anonymous token, credentials for '%s' are not sent to: %s://%s", o.registry, realm.Scheme, realm.Host)
+		return "", "", nil
+	}
+
+	password, err := registry.Token.Resolve()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read credentials for registry '%s': %s", o.registry, err.Error())
+	}
+
+	username := registry.Username
+	if username == "" {
+		username = "stacks-cli"
+	}
+
+	return username, password, nil
+}
+
+// parseDigest returns the hash from a sha256 digest. The hash must be 64 lowercase hex
+// characters as it is used as the name of the file in the cache
+func parseDigest(digest string) (string, error) {
+
+	algorithm, hash, found := strings.Cut(digest, ":")
+	if !found || algorithm != "sha256" || !ociDigestHash.MatchString(hash) {
+		return "", fmt.Errorf("unsupported digest: %s", digest)
+	}
+
+	return hash, nil
+}
+
+// verifyBlob checks that the SHA256 checksum of the blob matches the hash
+func verifyBlob(path string, hash string) error {
+
+	sum, err := util.FileChecksum(path, "sha256")
+	if err != nil {
+		return err
+	}
+
+	if actual := hex.EncodeToString(sum); actual != hash {
+		return fmt.Errorf("layer digest mismatch, expected 'sha256:%s' but found 'sha256:%s'", hash, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,267 @@
+package downloaders
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry returns a server that behaves like an OCI registry which requires bearer
+// token authentication. It serves a single artifact, with a template layer, at
+// stacks/infra-template:1.0.0
+func newTestRegistry(t *testing.T, layer []byte) (*httptest.Server, string) {
+
+	layerSum := sha256.Sum256(layer)
+	layerDigest := "sha256:" + hex.EncodeToString(layerSum[:])
+
+	manifest, err := json.Marshal(ociManifest{
+		MediaType: "application/vnd.oci.image.manifest.v1+json",
+		Layers: []ociDescriptor{
+			{
+				MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+				Digest:    layerDigest,
+				Size:      int64(len(layer)),
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	manifestSum := sha256.Sum256(manifest)
+	manifestDigest := "sha256:" + hex.EncodeToString(manifestSum[:])
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		// token endpoint
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:stacks/infra-template:pull", r.URL.Query().Get("scope"))
+			json.NewEncoder(w).Encode(ociToken{Token: "registry-token"})
+			return
+		}
+
+		// all other endpoints require the bearer token
+		if r.Header.Get("Authorization") != "Bearer registry-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test-registry",scope="repository:stacks/infra-template:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/stacks/infra-template/manifests/1.0.0", "/v2/stacks/infra-template/manifests/" + manifestDigest:
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			w.Write(manifest)
+		case "/v2/stacks/infra-template/blobs/" + layerDigest:
+			w.Write(layer)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return server, manifestDigest
+}
+
+func TestNewOCIDownloader(t *testing.T) {
+	downloader := NewOCIDownloader("infra_template", "ghcr.io/ensono/templates:1.0.0", "", 1, "/tmp/cache", "/tmp/oci")
+
+	assert.Equal(t, "infra_template", downloader.Name, "Name should be set correctly")
+	assert.Equal(t, "ghcr.io/ensono/templates:1.0.0", downloader.PackageURL(), "PackageURL should return the reference")
+	assert.Equal(t, 1, downloader.StripComponents, "StripComponents should be set correctly")
+}
+
+func TestOCI_ParseReference(t *testing.T) {
+
+	tests := []struct {
+		reference  string
+		version    string
+		scheme     string
+		registry   string
+		repository string
+		tag        string
+	}{
+		{"ghcr.io/ensono/templates:1.0.0", "", "https", "ghcr.io", "ensono/templates", "1.0.0"},
+		{"oci://ghcr.io/ensono/templates", "", "https", "ghcr.io", "ensono/templates", "latest"},
+		{"ghcr.io/ensono/templates", "2.0.0", "https", "ghcr.io", "ensono/templates", "2.0.0"},
+		{"http://localhost:5000/templates@sha256:abcd", "", "http", "localhost:5000", "templates", "sha256:abcd"},
+		{"localhost:5000/ensono/templates", "", "https", "localhost:5000", "ensono/templates", "latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			downloader := NewOCIDownloader("test", tt.reference, tt.version, 0, "", "")
+
+			err := downloader.parseReference()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.scheme, downloader.scheme, "Scheme should be parsed correctly")
+			assert.Equal(t, tt.registry, downloader.registry, "Registry should be parsed correctly")
+			assert.Equal(t, tt.repository, downloader.repository, "Repository should be parsed correctly")
+			assert.Equal(t, tt.tag, downloader.tag, "Tag should be parsed correctly")
+		})
+	}
+
+	downloader := NewOCIDownloader("test", "templates", "", 0, "", "")
+	assert.Error(t, downloader.parseReference(), "A reference without a registry should be rejected")
+}
+
+func TestOCI_Get(t *testing.T) {
+
+	layer := createTemplateTarball(t)
+	server, manifestDigest := newTestRegistry(t, layer)
+	defer server.Close()
+
+	registry := strings.Replace(server.URL, "http://", "", 1)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name      string
+		reference string
+		version   string
+	}{
+		{"Tag", fmt.Sprintf("http://%s/stacks/infra-template:1.0.0", registry), ""},
+		{"Version as tag", fmt.Sprintf("http://%s/stacks/infra-template", registry), "1.0.0"},
+		{"Digest", fmt.Sprintf("http://%s/stacks/infra-template@%s", registry, manifestDigest), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := filepath.Join(t.TempDir(), "temp")
+
+			downloader := NewOCIDownloader("infra_template", tt.reference, tt.version, 1, t.TempDir(), tempDir)
+			downloader.SetLogger(logger)
+
+			dir, err := downloader.Get()
+			require.NoError(t, err)
+
+			assert.Equal(t, filepath.Join(tempDir, "infra_template"), dir, "Template directory should be returned")
+			assert.FileExists(t, filepath.Join(dir, "stackscli.yml"), "Layer should be extracted")
+		})
+	}
+}
+
+func TestOCI_Get_Errors(t *testing.T) {
+
+	layer := createTemplateTarball(t)
+	server, _ := newTestRegistry(t, layer)
+	defer server.Close()
+
+	registry := strings.Replace(server.URL, "http://", "", 1)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name      string
+		reference string
+		wantErr   string
+	}{
+		{"Unknown tag", fmt.Sprintf("http://%s/stacks/infra-template:2.0.0", registry), "error retrieving manifest"},
+		{"Unknown digest", fmt.Sprintf("http://%s/stacks/infra-template@sha256:%s", registry, strings.Repeat("0", 64)), "error retrieving manifest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloader := NewOCIDownloader("infra_template", tt.reference, "", 0, t.TempDir(), t.TempDir())
+			downloader.SetLogger(logger)
+
+			_, err := downloader.Get()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOCI_Credentials(t *testing.T) {
+
+	t.Setenv("STACKSCLI_TEST_REGISTRY_TOKEN", "registry-password")
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	registries := []config.Registry{
+		{Host: "ghcr.io", Username: "octocat", Token: "env:STACKSCLI_TEST_REGISTRY_TOKEN"},
+		{Host: "registry-1.docker.io", Token: "env:STACKSCLI_TEST_REGISTRY_TOKEN", AuthHost: "auth.docker.io"},
+	}
+
+	tests := []struct {
+		name      string
+		reference string
+		realm     string
+		username  string
+		password  string
+	}{
+		{"Realm on the registry host", "ghcr.io/ensono/templates", "https://ghcr.io/token", "octocat", "registry-password"},
+		{"Realm on the auth host", "registry-1.docker.io/ensono/templates", "https://auth.docker.io/token", "stacks-cli", "registry-password"},
+		{"Realm on another host", "ghcr.io/ensono/templates", "https://attacker.example.com/token", "", ""},
+		{"Realm is not HTTPS", "ghcr.io/ensono/templates", "http://ghcr.io/token", "", ""},
+		{"Registry without credentials", "quay.io/ensono/templates", "https://quay.io/token", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloader := NewOCIDownloader("test", tt.reference, "", 0, "", "")
+			downloader.Registries = registries
+			downloader.SetLogger(logger)
+			require.NoError(t, downloader.parseReference())
+
+			realm, err := url.Parse(tt.realm)
+			require.NoError(t, err)
+
+			username, password, err := downloader.credentials(realm)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.username, username)
+			assert.Equal(t, tt.password, password)
+		})
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+
+	valid := strings.Repeat("a1", 32)
+
+	hash, err := parseDigest("sha256:" + valid)
+	require.NoError(t, err)
+	assert.Equal(t, valid, hash)
+
+	for _, digest := range []string{
+		"sha256:../../x",
+		"sha256:" + strings.Repeat("A1", 32),
+		"sha256:" + valid[:63],
+		"sha256:" + valid + "/",
+		"sha512:" + valid,
+		valid,
+	} {
+		_, err := parseDigest(digest)
+		assert.Error(t, err, "Digest should be rejected: %s", digest)
+	}
+}
+
+func TestLayerFilename(t *testing.T) {
+
+	for title, expected := range map[string]string{
+		"template.yml":         "template.yml",
+		"templates/stacks.yml": "stacks.yml",
+	} {
+		filename, err := layerFilename(title)
+		require.NoError(t, err, title)
+		assert.Equal(t, expected, filename, title)
+	}
+
+	for _, title := range []string{"..", ".", "/", "../", "templates/.."} {
+		_, err := layerFilename(title)
+		assert.Error(t, err, "Title should be rejected: %s", title)
+	}
+}
@@ -0,0 +1,92 @@
+package downloaders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/sirupsen/logrus"
+)
+
+// Plugin downloads a package by running an external executable. The executable is sent
+// the PackageRequest as JSON on stdin and must write the path to the directory that
+// the package has been extracted into as the last line of stdout. The subpath of the
+// package is resolved relative to that directory
+type Plugin struct {
+	Path    string
+	Request PackageRequest
+
+	// define private properties
+	logger *logrus.Logger
+}
+
+func NewPluginDownloader(path string, request PackageRequest) *Plugin {
+	return &Plugin{
+		Path:    path,
+		Request: request,
+	}
+}
+
+func (p *Plugin) Get() (string, error) {
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	p.logger.Infof("Using downloader plugin: %s", p.Path)
+
+	input, err := json.Marshal(p.Request)
+	if err != nil {
+		return "", fmt.Errorf("unable to create plugin request: %s", err.Error())
+	}
+
+	cmd := exec.Command(p.Path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+
+	// output anything the plugin has written to stderr as debug information
+	if stderr.Len() > 0 {
+		p.logger.Debugf("Plugin output:\n%s", stderr.String())
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("downloader plugin '%s' failed: %s\n%s", filepath.Base(p.Path), err.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	// the directory is the last line that has been written to stdout
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	dir := strings.TrimSpace(lines[len(lines)-1])
+
+	if dir == "" {
+		return "", fmt.Errorf("downloader plugin '%s' did not return a directory", filepath.Base(p.Path))
+	}
+
+	if !util.Exists(dir) {
+		return "", fmt.Errorf("directory returned by downloader plugin '%s' does not exist: %s", filepath.Base(p.Path), dir)
+	}
+
+	return resolveSubpath(dir, p.Request.Package.Subpath)
+}
+
+func (p *Plugin) PackageURL() string {
+
+	pkg := p.Request.Package
+
+	for _, ref := range []string{pkg.URL, pkg.Path, pkg.Name} {
+		if ref != "" {
+			return ref
+		}
+	}
+
+	return p.Path
+}
+
+func (p *Plugin) SetLogger(logger *logrus.Logger) {
+	p.logger = logger
+}
@@ -0,0 +1,95 @@
+package downloaders
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/Ensono/stacks-cli/pkg/interfaces"
+)
+
+// PluginPrefix is the prefix of the name of external downloader executables. The type of
+// package is appended to the prefix, e.g. `stacks-cli-downloader-s3`
+const PluginPrefix = "stacks-cli-downloader-"
+
+// PackageRequest holds the information that is required to create a downloader for a
+// package. It is also the document that is passed to external downloader plugins
+type PackageRequest struct {
+	Name             string            `json:"name"`
+	Package          config.Package    `json:"package"`
+	FrameworkVersion string            `json:"framework_version"`
+	CacheDir         string            `json:"cache_dir"`
+	TempDir          string            `json:"temp_dir"`
+	GitHubAPI        string            `json:"github_api,omitempty"`
+	Token            string            `json:"-"`
+	NoVerify         bool              `json:"no_verify,omitempty"`
+	Registries       []config.Registry `json:"-"`
+}
+
+// Factory creates a downloader for the package in the request
+type Factory func(request PackageRequest) (interfaces.Downloader, error)
+
+var (
+	registry   = make(map[string]Factory)
+	registryMu sync.RWMutex
+
+	// lookPath is used to find external downloader plugins, it is a variable
+	// so that it can be overridden in tests
+	lookPath = exec.LookPath
+)
+
+// Register adds the factory for the named package type to the registry. If the type has
+// already been registered it is replaced
+func Register(packageType string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[strings.ToLower(packageType)] = factory
+}
+
+// Types returns a sorted list of the package types that have been registered
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for name := range registry {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// New creates the downloader for the type of package in the request. If the type has not
+// been registered then the PATH is searched for an external downloader plugin
+func New(request PackageRequest) (interfaces.Downloader, error) {
+
+	packageType := strings.ToLower(request.Package.Type)
+
+	registryMu.RLock()
+	factory, ok := registry[packageType]
+	registryMu.RUnlock()
+
+	if ok {
+		return factory(request)
+	}
+
+	// attempt to find a plugin for the package type
+	if packageType != "" {
+		if path, err := lookPath(PluginPrefix + packageType); err == nil {
+			return NewPluginDownloader(path, request), nil
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"unsupported package type '%s', supported types are %s, or an executable named '%s%s' must be in the PATH",
+		request.Package.Type,
+		strings.Join(Types(), ", "),
+		PluginPrefix,
+		packageType,
+	)
+}
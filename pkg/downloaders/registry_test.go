@@ -0,0 +1,156 @@
+package downloaders
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypes(t *testing.T) {
+	types := Types()
+
+	for _, name := range []string{"archive", "filesystem", "git", "local", "nuget", "oci"} {
+		assert.Contains(t, types, name, "Built in downloader should be registered")
+	}
+}
+
+func TestNew(t *testing.T) {
+
+	// ensure that plugins are not found on the PATH of the machine running the tests
+	defer func(fn func(string) (string, error)) { lookPath = fn }(lookPath)
+	lookPath = func(file string) (string, error) {
+		return "", errors.New("not found")
+	}
+
+	tests := []struct {
+		name    string
+		pkg     config.Package
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "Git",
+			pkg:  config.Package{Type: "git", URL: "https://github.com/Ensono/stacks-infrastructure-aks"},
+			want: &Git{},
+		},
+		{
+			name:    "Git with invalid URL",
+			pkg:     config.Package{Type: "git", URL: "stacks-infrastructure-aks"},
+			wantErr: "URL is invalid",
+		},
+		{
+			name: "Nuget",
+			pkg:  config.Package{Type: "nuget", Name: "Ensono.Stacks.Templates", ID: "webapi"},
+			want: &Nuget{},
+		},
+		{
+			name: "Local",
+			pkg:  config.Package{Type: "local", Path: "/tmp/template"},
+			want: &Filesystem{},
+		},
+		{
+			name: "Case insensitive type",
+			pkg:  config.Package{Type: "OCI", URL: "ghcr.io/ensono/templates:1.0.0"},
+			want: &OCI{},
+		},
+		{
+			name:    "Unknown type",
+			pkg:     config.Package{Type: "s3"},
+			wantErr: "unsupported package type 's3'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloader, err := New(PackageRequest{Name: "test", Package: tt.pkg})
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, downloader, "No downloader should be returned on error")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, downloader)
+		})
+	}
+}
+
+func TestNew_Plugin(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin test uses a shell script")
+	}
+
+	// create a plugin that copies the request to the directory that it returns
+	pluginDir := t.TempDir()
+	outputDir := t.TempDir()
+	script := "#!/bin/sh\necho 'fetching package' >&2\ncat > " + filepath.Join(outputDir, "request.json") + "\necho " + outputDir + "\n"
+	err := os.WriteFile(filepath.Join(pluginDir, PluginPrefix+"s3"), []byte(script), 0755)
+	require.NoError(t, err)
+
+	t.Setenv("PATH", pluginDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	downloader, err := New(PackageRequest{
+		Name:    "infra_template",
+		Package: config.Package{Type: "s3", URL: "s3://templates/infra.tar.gz"},
+		Token:   "secret",
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &Plugin{}, downloader)
+	assert.Equal(t, "s3://templates/infra.tar.gz", downloader.PackageURL())
+
+	downloader.SetLogger(logger)
+	dir, err := downloader.Get()
+	require.NoError(t, err)
+	assert.Equal(t, outputDir, dir, "Directory returned by the plugin should be used")
+
+	request, err := os.ReadFile(filepath.Join(outputDir, "request.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(request), `"url":"s3://templates/infra.tar.gz"`, "Package should be sent to the plugin")
+	assert.NotContains(t, string(request), "secret", "Token should not be sent to the plugin")
+}
+
+func TestPlugin_Get_Errors(t *testing.T) {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugin test uses a shell script")
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{"Plugin fails", "#!/bin/sh\necho 'access denied' >&2\nexit 1\n", "access denied"},
+		{"No directory", "#!/bin/sh\ncat > /dev/null\n", "did not return a directory"},
+		{"Missing directory", "#!/bin/sh\necho /does/not/exist\n", "does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), PluginPrefix+"test")
+			err := os.WriteFile(path, []byte(tt.script), 0755)
+			require.NoError(t, err)
+
+			downloader := NewPluginDownloader(path, PackageRequest{})
+			downloader.SetLogger(logger)
+
+			_, err = downloader.Get()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package downloaders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveSubpath returns the directory within the downloaded package that contains the
+// template. The subpath must be relative and cannot refer to a location outside of the package
+func resolveSubpath(dir string, subpath string) (string, error) {
+
+	if subpath == "" {
+		return dir, nil
+	}
+
+	clean := filepath.Clean(filepath.FromSlash(subpath))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("subpath must be a relative path within the package: %s", subpath)
+	}
+
+	path := filepath.Join(dir, clean)
+
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("subpath '%s' does not exist in the package", subpath)
+	}
+
+	return path, nil
+}
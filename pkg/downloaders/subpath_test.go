@@ -0,0 +1,87 @@
+package downloaders
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSubpath(t *testing.T) {
+
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "templates", "infra"), os.ModePerm)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		subpath string
+		want    string
+		wantErr string
+	}{
+		{"No subpath", "", dir, ""},
+		{"Nested directory", "templates/infra", filepath.Join(dir, "templates", "infra"), ""},
+		{"Trailing slash", "templates/infra/", filepath.Join(dir, "templates", "infra"), ""},
+		{"Missing directory", "templates/webapi", "", "does not exist in the package"},
+		{"Outside of package", "../templates", "", "must be a relative path within the package"},
+		{"Absolute path", "/templates", "", "must be a relative path within the package"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveSubpath(dir, tt.subpath)
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNew_Subpath(t *testing.T) {
+
+	// create a repository that contains several templates
+	repoDir := t.TempDir()
+	for _, name := range []string{"infra", "webapi"} {
+		templateDir := filepath.Join(repoDir, "templates", name)
+		require.NoError(t, os.MkdirAll(templateDir, os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(templateDir, "stackscli.yml"), []byte("framework:\n  name: "+name+"\n"), 0644))
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	tempDir := filepath.Join(t.TempDir(), "temp")
+
+	// the filesystem downloader is shared, so ensure the subpath does not affect other tests
+	defer func() { filesystem.Subpath = "" }()
+
+	downloader, err := New(PackageRequest{
+		Name: "webapi",
+		Package: config.Package{
+			Type:    "filesystem",
+			Path:    repoDir,
+			Subpath: "templates/webapi",
+		},
+		TempDir: tempDir,
+	})
+	require.NoError(t, err)
+	downloader.SetLogger(logger)
+
+	dir, err := downloader.Get()
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(tempDir, "templates", "webapi"), dir, "Subpath should be returned as the template directory")
+
+	content, err := os.ReadFile(filepath.Join(dir, "stackscli.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name: webapi")
+}
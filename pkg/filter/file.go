@@ -0,0 +1,217 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/go-git/go-billy/v5"
+	"gopkg.in/yaml.v2"
+)
+
+// ReadFile reads the YAML file into the subset so that values can be removed from it
+func (y *yamlSubset) ReadFile(bfs billy.Filesystem, path string) error {
+
+	data, err := util.ReadFile(bfs, path)
+	if err != nil {
+		return err
+	}
+
+	subset := make(map[string]interface{})
+	err = yaml.Unmarshal(data, &subset)
+	if err != nil {
+		return fmt.Errorf("unable to parse file '%s': %s", path, err.Error())
+	}
+
+	y.data = subset
+
+	return nil
+}
+
+// Has states if the subset contains the top level key
+func (y *yamlSubset) Has(key string) bool {
+	_, ok := lookupKey(y.data, key)
+	return ok
+}
+
+// Set sets the value at the dotted path in the subset, creating any maps that do not exist.
+// The keys are matched without case. Items in lists are set using an index, e.g. `project[0]`,
+// an item can be added to the end of the list by using the next index
+func (y *yamlSubset) Set(path string, value interface{}) error {
+
+	segments, err := util.ParseDottedPath(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = setKey(y.data, segments, value, "")
+
+	return err
+}
+
+// Remove deletes the value at the dotted path from the subset, the keys are matched without
+// case. Maps that are empty once the value has been removed are also removed. It returns
+// false if the path does not exist
+func (y *yamlSubset) Remove(path string) bool {
+
+	segments, err := util.ParseDottedPath(path)
+	if err != nil {
+		return false
+	}
+
+	return removeKey(y.data, segments)
+}
+
+// setKey sets the value in the map at the path of the segments and returns the map, which is
+// created if the data is not a map
+func setKey(data interface{}, segments []util.PathSegment, value interface{}, parent string) (interface{}, error) {
+
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	if _, ok := data.(map[interface{}]interface{}); !ok {
+		if _, ok := data.(map[string]interface{}); !ok {
+			data = make(map[string]interface{})
+		}
+	}
+
+	segment := segments[0]
+	path := segment.Name
+	if parent != "" {
+		path = fmt.Sprintf("%s.%s", parent, segment.Name)
+	}
+
+	key, ok := lookupKey(data, segment.Name)
+	if !ok {
+		key = segment.Name
+	}
+
+	child := mapValue(data, key)
+
+	var err error
+	if segment.HasIndex() {
+		list, _ := child.([]interface{})
+		if segment.Index > len(list) {
+			return nil, fmt.Errorf("index %d is out of range for '%s', it has %d items", segment.Index, path, len(list))
+		}
+
+		if segment.Index == len(list) {
+			list = append(list, nil)
+		}
+
+		list[segment.Index], err = setKey(list[segment.Index], segments[1:], value, fmt.Sprintf("%s[%d]", path, segment.Index))
+		child = list
+	} else {
+		child, err = setKey(child, segments[1:], value, path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	setValue(data, key, child)
+
+	return data, nil
+}
+
+func removeKey(data interface{}, segments []util.PathSegment) bool {
+
+	segment := segments[0]
+
+	key, ok := lookupKey(data, segment.Name)
+	if !ok {
+		return false
+	}
+
+	if segment.HasIndex() {
+		list, ok := mapValue(data, key).([]interface{})
+		if !ok || segment.Index >= len(list) {
+			return false
+		}
+
+		// remove the item from the list, or the value from within the item
+		if len(segments) == 1 {
+			list = append(list[:segment.Index], list[segment.Index+1:]...)
+		} else if !removeKey(list[segment.Index], segments[1:]) {
+			return false
+		}
+
+		if len(list) > 0 {
+			setValue(data, key, list)
+			return true
+		}
+
+	} else if len(segments) > 1 {
+		child := mapValue(data, key)
+		if !removeKey(child, segments[1:]) {
+			return false
+		}
+
+		// remove the parent if it no longer has any values
+		if !isEmptyMap(child) {
+			return true
+		}
+	}
+
+	switch m := data.(type) {
+	case map[string]interface{}:
+		delete(m, key.(string))
+	case map[interface{}]interface{}:
+		delete(m, key)
+	}
+
+	return true
+}
+
+// lookupKey finds the key in the map without case and returns the key as it is in the map
+func lookupKey(data interface{}, name string) (interface{}, bool) {
+
+	switch m := data.(type) {
+	case map[string]interface{}:
+		for key := range m {
+			if strings.EqualFold(key, name) {
+				return key, true
+			}
+		}
+	case map[interface{}]interface{}:
+		for key := range m {
+			if strings.EqualFold(fmt.Sprintf("%v", key), name) {
+				return key, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func mapValue(data interface{}, key interface{}) interface{} {
+	switch m := data.(type) {
+	case map[string]interface{}:
+		return m[key.(string)]
+	case map[interface{}]interface{}:
+		return m[key]
+	}
+
+	return nil
+}
+
+func setValue(data interface{}, key interface{}, value interface{}) {
+	switch m := data.(type) {
+	case map[string]interface{}:
+		m[key.(string)] = value
+	case map[interface{}]interface{}:
+		m[key] = value
+	}
+}
+
+func isEmptyMap(data interface{}) bool {
+	switch m := data.(type) {
+	case map[string]interface{}:
+		return len(m) == 0
+	case map[interface{}]interface{}:
+		return len(m) == 0
+	}
+
+	return false
+}
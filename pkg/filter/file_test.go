@@ -0,0 +1,70 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFileRemove(t *testing.T) {
+
+	fs := memfs.New()
+	err := util.WriteFile(fs, "config.yml", []byte(`input:
+  business:
+    company: ensono
+    domain: core
+  terraform:
+    backend:
+      group: tfstate
+`), 0644)
+	require.NoError(t, err)
+
+	subset := New()
+	require.NoError(t, subset.ReadFile(fs, "config.yml"))
+	assert.True(t, subset.Has("Input"))
+
+	assert.True(t, subset.Remove("input.Business.Domain"))
+	assert.True(t, subset.Remove("input.terraform.backend.group"))
+	assert.False(t, subset.Remove("input.terraform.backend.group"), "removing a value that is not set")
+	assert.False(t, subset.Remove("input.business.company.name"), "removing a value beneath a string")
+
+	assert.Equal(t, "input:\n  business:\n    company: ensono\n", subset.String())
+
+	assert.Error(t, subset.ReadFile(fs, "missing.yml"))
+}
+
+func TestSet(t *testing.T) {
+
+	subset := New()
+
+	require.NoError(t, subset.Set("input.business.company", "ensono"))
+	require.NoError(t, subset.Set("input.project[0].name", "first"))
+	require.NoError(t, subset.Set("input.project[1].cloud.region", "westeurope"))
+	require.NoError(t, subset.Set("input.Business.domain", "core"))
+	require.NoError(t, subset.Set("input.business.Company", "ensono"))
+	require.NoError(t, subset.Set("input.options.dryrun", true))
+
+	assert.EqualError(t, subset.Set("input.project[3].name", "x"), "index 3 is out of range for 'input.project', it has 2 items")
+
+	assert.Equal(t, `input:
+  business:
+    company: ensono
+    domain: core
+  options:
+    dryrun: true
+  project:
+  - name: first
+  - cloud:
+      region: westeurope
+`, subset.String())
+
+	assert.True(t, subset.Remove("input.project[1].cloud.region"))
+	assert.True(t, subset.Remove("input.project[0]"))
+	assert.False(t, subset.Remove("input.project[5]"))
+	assert.True(t, subset.Remove("input.project[0]"))
+	assert.False(t, subset.Has("project"))
+	assert.NotContains(t, subset.String(), "project")
+}
@@ -0,0 +1,101 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/Ensono/stacks-cli/pkg/config"
+	"golang.org/x/term"
+)
+
+// askParameter prompts the user for the value of a parameter, it is a variable so that
+// it can be overridden in tests
+var askParameter = func(prompt survey.Prompt, value *string, validate survey.Validator) error {
+	return survey.AskOne(prompt, value, survey.WithValidator(validate))
+}
+
+// isInteractive states if the user can be prompted for values
+var isInteractive = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// resolveParameters checks the variables for the project against the parameters declared in
+// the project settings. Parameters that do not have a value are prompted for if the CLI is
+// being run interactively, otherwise an error listing all of the problems is returned.
+// The values of the parameters are added to the project variables so they are available
+// to the templates
+func (s *Scaffold) resolveParameters(project *config.Project) error {
+
+	if len(project.Settings.Parameters) == 0 {
+		return nil
+	}
+
+	values, missing, errs := project.Settings.ResolveParameters(project.GetVariables(s.Config.Input))
+
+	if len(missing) > 0 && len(errs) == 0 && isInteractive() {
+		for _, param := range missing {
+
+			var value string
+			err := askParameter(param.GetPrompt(), &value, parameterValidator(param))
+			if err != nil {
+				return fmt.Errorf("unable to get value for parameter '%s': %s", param.Name, err.Error())
+			}
+
+			values[param.GetKey()] = value
+		}
+
+		missing = nil
+	}
+
+	for _, param := range missing {
+		msg := "a value has not been supplied"
+		if param.Description != "" {
+			msg = fmt.Sprintf("%s, %s", msg, param.Description)
+		}
+		errs = append(errs, config.ParameterError{Name: param.Name, Message: msg})
+	}
+
+	if len(errs) > 0 {
+		lines := make([]string, len(errs))
+		for i, err := range errs {
+			lines[i] = fmt.Sprintf("\t- %s", err.Error())
+		}
+
+		return fmt.Errorf("the parameters for the project are not valid, please set them using `project.variables` or `--var`:\n%s", strings.Join(lines, "\n"))
+	}
+
+	// add the values to the project variables, without modifying the map from the configuration
+	vars := make(map[string]string, len(project.Variables)+len(values))
+	for key, value := range project.Variables {
+		vars[key] = value
+	}
+	for key, value := range values {
+		vars[key] = value
+	}
+	project.Variables = vars
+
+	return nil
+}
+
+// parameterValidator returns a validator for the prompt that checks the answer against
+// the parameter
+func parameterValidator(param config.Parameter) survey.Validator {
+	return func(ans interface{}) error {
+
+		var value string
+
+		switch answer := ans.(type) {
+		case string:
+			value = answer
+		case core.OptionAnswer:
+			value = answer.Value
+		default:
+			value = fmt.Sprintf("%v", ans)
+		}
+
+		return param.Validate(value)
+	}
+}
@@ -0,0 +1,74 @@
+package scaffold
+
+import (
+	"testing"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/Ensono/stacks-cli/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parametersTestProject() *config.Project {
+
+	project := &config.Project{
+		Variables: map[string]string{"team": "platform"},
+	}
+	project.Settings.Parameters = []config.Parameter{
+		{Name: "team"},
+		{Name: "email", Description: "Email address of the team", Regex: `@`},
+		{Name: "region", Default: "westeurope"},
+	}
+
+	return project
+}
+
+func TestResolveParametersInteractive(t *testing.T) {
+
+	defer func(interactive func() bool, ask func(survey.Prompt, *string, survey.Validator) error) {
+		isInteractive = interactive
+		askParameter = ask
+	}(isInteractive, askParameter)
+
+	var asked []string
+	isInteractive = func() bool { return true }
+	askParameter = func(prompt survey.Prompt, value *string, validate survey.Validator) error {
+		asked = append(asked, prompt.(*survey.Input).Message)
+		*value = "team@example.com"
+		return validate(*value)
+	}
+
+	project := parametersTestProject()
+	scaffold := New(&config.Config{}, log.New())
+
+	err := scaffold.resolveParameters(project)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Email address of the team (email)"}, asked)
+	assert.Equal(t, map[string]string{
+		"team":   "platform",
+		"email":  "team@example.com",
+		"region": "westeurope",
+	}, project.Variables)
+}
+
+func TestResolveParametersNonInteractive(t *testing.T) {
+
+	defer func(interactive func() bool) {
+		isInteractive = interactive
+	}(isInteractive)
+
+	isInteractive = func() bool { return false }
+
+	project := parametersTestProject()
+
+	scaffold := New(&config.Config{}, log.New())
+	err := scaffold.resolveParameters(project)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "- email: a value has not been supplied, Email address of the team")
+
+	// the project variables must not be changed if the parameters are not valid
+	assert.NotContains(t, project.Variables, "email")
+}
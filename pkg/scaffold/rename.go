@@ -0,0 +1,254 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+)
+
+// renameRule is a rename from an operation with the replacement value rendered
+// and both values split into path segments
+type renameRule struct {
+	from    []string
+	to      []string
+	pkg     bool
+	literal string
+	value   string
+}
+
+// renamer determines the name that files and directories are given when they are
+// copied from the template into the project
+type renamer struct {
+	config       *config.Config
+	replacements config.Replacements
+	options      config.RenderOptions
+	rules        []renameRule
+
+	// render states if template tokens in the names of files and directories are
+	// rendered, this is off by default so that names that contain the delimiters,
+	// such as GitHub Actions files, are copied as they are
+	render bool
+
+	// partial holds the directories in the project that have been created for
+	// part of a package path that has been renamed, these are removed after the
+	// copy if they are empty
+	partial   []string
+	partialMu sync.Mutex
+}
+
+// newRenamer renders the rename rules of the operation
+func newRenamer(conf *config.Config, rules []config.Rename, render bool, replacements config.Replacements, options config.RenderOptions) (*renamer, error) {
+
+	r := &renamer{
+		config:       conf,
+		replacements: replacements,
+		options:      options,
+		render:       render,
+	}
+
+	for i, rule := range rules {
+
+		if rule.From == "" {
+			return nil, fmt.Errorf("rename rule %d does not have a 'from' value", i+1)
+		}
+
+		to, err := conf.RenderTemplateWithOptions("rename", rule.To, replacements, options)
+		if err != nil {
+			return nil, fmt.Errorf("unable to render rename value for '%s': %s", rule.From, err.Error())
+		}
+
+		item := renameRule{
+			pkg:     rule.Package,
+			literal: rule.From,
+			value:   to,
+		}
+
+		// package names are expanded so that each part of the package is a directory
+		if rule.Package {
+			item.from = splitPackage(rule.From)
+			item.to = splitPackage(to)
+
+			if len(item.from) == 0 || len(item.to) == 0 {
+				return nil, fmt.Errorf("rename rule for package '%s' resolves to an empty package name", rule.From)
+			}
+
+			for _, segment := range item.to {
+				if err := checkSegment(segment); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		r.rules = append(r.rules, item)
+	}
+
+	return r, nil
+}
+
+// Path returns the relative path that the item at the relative path in the template
+// should have in the project
+func (r *renamer) Path(rel string) (string, error) {
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+
+	// expand the package paths first as they can span several segments
+	for _, rule := range r.rules {
+		if rule.pkg {
+			segments = replaceSegments(segments, rule.from, rule.to)
+		}
+	}
+
+	left, _ := r.options.Delimiters.Get()
+
+	for i, segment := range segments {
+
+		for _, rule := range r.rules {
+			if !rule.pkg {
+				segment = strings.ReplaceAll(segment, rule.literal, rule.value)
+			}
+		}
+
+		// render any tokens in the name through the template engine, if this has been turned on
+		if r.render && strings.Contains(segment, left) {
+			rendered, err := r.config.RenderTemplateWithOptions(segment, segment, r.replacements, r.options)
+			if err != nil {
+				return "", fmt.Errorf("unable to render path '%s': %s", rel, err.Error())
+			}
+			segment = rendered
+		}
+
+		if err := checkSegment(segment); err != nil {
+			return "", fmt.Errorf("path '%s' cannot be renamed: %s", rel, err.Error())
+		}
+
+		segments[i] = segment
+	}
+
+	return filepath.FromSlash(strings.Join(segments, "/")), nil
+}
+
+// Destination is used by the copy to rename items as they are copied from the
+// source directory to the destination directory
+func (r *renamer) Destination(srcDir string, destDir string) func(src, dest string) (string, error) {
+
+	return func(src, dest string) (string, error) {
+
+		rel, err := filepath.Rel(srcDir, src)
+		if err != nil {
+			return "", err
+		}
+
+		if rel == "." {
+			return dest, nil
+		}
+
+		renamed, err := r.Path(rel)
+		if err != nil {
+			return "", err
+		}
+
+		result := filepath.Join(destDir, renamed)
+		if r.isPartial(rel) {
+			r.partialMu.Lock()
+			r.partial = append(r.partial, result)
+			r.partialMu.Unlock()
+		}
+
+		return result, nil
+	}
+}
+
+// Cleanup removes the directories that were created for part of a package path
+// that has been moved, if they are empty
+func (r *renamer) Cleanup() {
+
+	// remove the deepest directories first so that their parents can be removed
+	sort.Slice(r.partial, func(i, j int) bool {
+		return len(r.partial[i]) > len(r.partial[j])
+	})
+
+	for _, dir := range r.partial {
+		_ = os.Remove(dir)
+	}
+}
+
+// isPartial states if the path ends with the start of a package that is renamed
+func (r *renamer) isPartial(rel string) bool {
+
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+
+	for _, rule := range r.rules {
+		if !rule.pkg {
+			continue
+		}
+
+		for n := 1; n < len(rule.from) && n <= len(segments); n++ {
+			if equalSegments(segments[len(segments)-n:], rule.from[:n]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// replaceSegments replaces each occurrence of the from segments in the path with
+// the to segments
+func replaceSegments(segments []string, from []string, to []string) []string {
+
+	var result []string
+
+	for i := 0; i < len(segments); {
+		if i+len(from) <= len(segments) && equalSegments(segments[i:i+len(from)], from) {
+			result = append(result, to...)
+			i += len(from)
+			continue
+		}
+
+		result = append(result, segments[i])
+		i++
+	}
+
+	return result
+}
+
+func equalSegments(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitPackage splits a Java package name, or a path, into its segments
+func splitPackage(name string) []string {
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return r == '.' || r == '/' || r == '\\'
+	})
+}
+
+// checkSegment ensures that a renamed path segment cannot move the item outside of
+// the directory it is being copied to
+func checkSegment(segment string) error {
+	switch {
+	case segment == "":
+		return fmt.Errorf("name is empty")
+	case segment == "." || segment == "..":
+		return fmt.Errorf("name '%s' is not allowed", segment)
+	case strings.ContainsAny(segment, `/\`):
+		return fmt.Errorf("name '%s' contains a path separator", segment)
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func renameTestConfig() (*config.Config, *config.Project) {
+
+	cfg := &config.Config{}
+	cfg.Input.Business.Company = "Acme"
+	cfg.Input.Business.Domain = "Payments"
+
+	project := &config.Project{Name: "my-webapi"}
+
+	return cfg, project
+}
+
+func TestRenamerPath(t *testing.T) {
+
+	cfg, project := renameTestConfig()
+
+	rules := []config.Rename{
+		{From: "__project__", To: "{{ .Project.Name }}"},
+		{From: "__escape__", To: "../outside"},
+		{From: "com.ensono.stacks", To: "com.{{ .Input.Business.Company | toLower }}.{{ .Input.Business.Domain | toLower }}", Package: true},
+	}
+
+	r, err := newRenamer(cfg, rules, true, config.Replacements{Input: cfg.Input, Project: *project}, config.RenderOptions{Strict: true})
+	require.NoError(t, err)
+
+	tables := []struct {
+		path     string
+		expected string
+		err      bool
+	}{
+		{"README.md", "README.md", false},
+		{"deploy/__project__.tf", "deploy/my-webapi.tf", false},
+		{"src/main/java/com/ensono/stacks/Application.java", "src/main/java/com/acme/payments/Application.java", false},
+		{"src/main/java/com/ensono", "src/main/java/com/ensono", false},
+		{"{{ .Input.Business.Company }}/main.tf", "Acme/main.tf", false},
+		{"{{ .Missing }}/main.tf", "", true},
+		{"__escape__.tf", "", true},
+	}
+
+	for _, table := range tables {
+		res, err := r.Path(table.path)
+
+		if table.err {
+			assert.Error(t, err, "Renaming '%s' should fail", table.path)
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, filepath.FromSlash(table.expected), res)
+	}
+}
+
+func TestRenamerPathNoRender(t *testing.T) {
+
+	cfg, project := renameTestConfig()
+
+	rules := []config.Rename{
+		{From: "__project__", To: "{{ .Project.Name }}"},
+	}
+
+	r, err := newRenamer(cfg, rules, false, config.Replacements{Input: cfg.Input, Project: *project}, config.RenderOptions{Strict: true})
+	require.NoError(t, err)
+
+	// names that contain the delimiters are left as they are unless rendering has been turned on
+	tables := []struct {
+		path     string
+		expected string
+	}{
+		{"deploy/__project__.tf", "deploy/my-webapi.tf"},
+		{"{{ .Missing }}/main.tf", "{{ .Missing }}/main.tf"},
+		{"{{cookiecutter.project}}/__project__.md", "{{cookiecutter.project}}/my-webapi.md"},
+	}
+
+	for _, table := range tables {
+		res, err := r.Path(table.path)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.FromSlash(table.expected), res)
+	}
+}
+
+func TestNewRenamerErrors(t *testing.T) {
+
+	cfg, project := renameTestConfig()
+	replacements := config.Replacements{Input: cfg.Input, Project: *project}
+
+	tables := []struct {
+		rule config.Rename
+		msg  string
+	}{
+		{config.Rename{To: "value"}, "A rule without a from value should fail"},
+		{config.Rename{From: "com.ensono", To: "", Package: true}, "A package that renders to nothing should fail"},
+	}
+
+	for _, table := range tables {
+		_, err := newRenamer(cfg, []config.Rename{table.rule}, false, replacements, config.RenderOptions{Strict: true})
+		assert.Error(t, err, table.msg)
+	}
+}
+
+func TestPerformOperationCopyRename(t *testing.T) {
+
+	tempDir := t.TempDir()
+	cfg, project := renameTestConfig()
+
+	templateDir := filepath.Join(tempDir, "template")
+	files := []string{
+		"__project__.tf",
+		"src/main/java/com/ensono/stacks/Application.java",
+		"src/main/java/com/ensono/stacks/api/Controller.java",
+	}
+	for _, name := range files {
+		file := filepath.Join(templateDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(name), 0644))
+	}
+
+	projectDir := filepath.Join(tempDir, "project")
+
+	scaffold := New(cfg, log.New())
+	operation := config.Operation{
+		Action: "copy",
+		Rename: []config.Rename{
+			{From: "__project__", To: "{{ .Project.Name }}"},
+			{From: "com.ensono.stacks", To: "com.acme.payments", Package: true},
+		},
+	}
+
+	err := scaffold.PerformOperation(operation, project, projectDir, templateDir)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(projectDir, "my-webapi.tf"))
+	assert.FileExists(t, filepath.Join(projectDir, "src", "main", "java", "com", "acme", "payments", "Application.java"))
+	assert.FileExists(t, filepath.Join(projectDir, "src", "main", "java", "com", "acme", "payments", "api", "Controller.java"))
+
+	// the directories of the original package should not be left behind
+	assert.NoDirExists(t, filepath.Join(projectDir, "src", "main", "java", "com", "ensono"))
+}
+
+func TestPerformOperationCopyRenderNames(t *testing.T) {
+
+	tempDir := t.TempDir()
+	cfg, project := renameTestConfig()
+
+	templateDir := filepath.Join(tempDir, "template")
+	file := filepath.Join(templateDir, "{{ .Project.Name }}.md")
+	require.NoError(t, os.MkdirAll(templateDir, 0755))
+	require.NoError(t, os.WriteFile(file, []byte("readme"), 0644))
+
+	scaffold := New(cfg, log.New())
+
+	// the name is rendered when this has been turned on
+	projectDir := filepath.Join(tempDir, "render")
+	err := scaffold.PerformOperation(config.Operation{Action: "copy", RenderNames: true}, project, projectDir, templateDir)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(projectDir, "my-webapi.md"))
+
+	// otherwise the file keeps its name
+	projectDir = filepath.Join(tempDir, "verbatim")
+	err = scaffold.PerformOperation(config.Operation{Action: "copy"}, project, projectDir, templateDir)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(projectDir, "{{ .Project.Name }}.md"))
+
+	// a name that cannot be rendered fails the copy
+	require.NoError(t, os.WriteFile(filepath.Join(templateDir, "{{ .Missing }}.md"), []byte("missing"), 0644))
+	err = scaffold.PerformOperation(config.Operation{Action: "copy", RenderNames: true}, project, filepath.Join(tempDir, "error"), templateDir)
+	assert.Error(t, err)
+}
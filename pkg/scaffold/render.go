@@ -0,0 +1,124 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/config"
+)
+
+// renderTemplates renders the template files in the project through the template engine.
+// Files with the template suffix are written out without the suffix and the template is
+// removed, files that match the items in the render settings are rendered in place
+func (s *Scaffold) renderTemplates(project *config.Project) error {
+
+	settings := project.Settings.Render
+	if !settings.Enabled {
+		return nil
+	}
+
+	if s.Config.Input.Options.DryRun {
+		s.Logger.Warn("Not rendering template files as in DRYRUN mode")
+		return nil
+	}
+
+	s.Logger.Info("Rendering template files")
+
+	replacements := config.NewReplacements(s.Config.Input, *project)
+
+	root := project.Directory.WorkingDir
+	suffix := settings.GetSuffix()
+	items := util.NewIgnoreMatcher(settings.Items)
+
+	var files []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if isTemplateFile(info.Name(), suffix) || items.Match(rel, false) {
+			files = append(files, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to find template files: %s", err.Error())
+	}
+
+	for _, rel := range files {
+		if err := s.renderTemplateFile(root, rel, suffix, replacements, project.Settings.GetRenderOptions(true)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderTemplateFile renders a single file in the project
+func (s *Scaffold) renderTemplateFile(root string, rel string, suffix string, replacements config.Replacements, options config.RenderOptions) error {
+
+	path := filepath.Join(root, filepath.FromSlash(rel))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read template file '%s': %s", rel, err.Error())
+	}
+
+	rendered, err := s.Config.RenderTemplateWithOptions(rel, string(content), replacements, options)
+	if err != nil {
+		return fmt.Errorf("unable to render template file '%s': %s", rel, err.Error())
+	}
+
+	target := path
+	if isTemplateFile(filepath.Base(path), suffix) {
+		target = strings.TrimSuffix(path, suffix)
+	}
+
+	s.Logger.Debugf("Rendering template file: %s", rel)
+
+	err = os.WriteFile(target, []byte(rendered), info.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("unable to write rendered file '%s': %s", rel, err.Error())
+	}
+
+	if target != path {
+		err = os.Remove(path)
+		if err != nil {
+			return fmt.Errorf("unable to remove template file '%s': %s", rel, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// isTemplateFile states if the file has the template suffix
+func isTemplateFile(name string, suffix string) bool {
+	return strings.HasSuffix(name, suffix) && name != suffix
+}
@@ -0,0 +1,114 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplates(t *testing.T) {
+
+	tables := []struct {
+		name     string
+		render   config.Render
+		files    map[string]string
+		expected map[string]string
+		missing  []string
+	}{
+		{
+			"disabled",
+			config.Render{},
+			map[string]string{
+				"README.md.tmpl": "# {{ .Project.Name }}",
+			},
+			map[string]string{
+				"README.md.tmpl": "# {{ .Project.Name }}",
+			},
+			[]string{"README.md"},
+		},
+		{
+			"suffix",
+			config.Render{Enabled: true},
+			map[string]string{
+				"README.md.tmpl":             "# {{ .Project.Name }}",
+				"deploy/terraform.tfvars":    "company = \"{{ .Input.Business.Company }}\"",
+				"deploy/main.tf.tmpl":        "# {{ .Input.Business.Company | toLower }}",
+				".tmpl":                      "{{ .Project.Name }}",
+				".git/hooks/pre-commit.tmpl": "{{ .Project.Name }}",
+			},
+			map[string]string{
+				"README.md":               "# my-webapi",
+				"deploy/terraform.tfvars": "company = \"{{ .Input.Business.Company }}\"",
+				"deploy/main.tf":          "# acme",
+				".tmpl":                   "{{ .Project.Name }}",
+			},
+			[]string{"README.md.tmpl", "deploy/main.tf.tmpl"},
+		},
+		{
+			"items",
+			config.Render{Enabled: true, Suffix: "tpl", Items: []string{"*.tfvars"}},
+			map[string]string{
+				"values.yaml.tpl":         "name: {{ .Project.Name }}",
+				"deploy/terraform.tfvars": "company = \"{{ .Input.Business.Company }}\"",
+			},
+			map[string]string{
+				"values.yaml":             "name: my-webapi",
+				"deploy/terraform.tfvars": "company = \"Acme\"",
+			},
+			[]string{"values.yaml.tpl"},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+
+			projectDir := t.TempDir()
+			for name, content := range table.files {
+				file := filepath.Join(projectDir, filepath.FromSlash(name))
+				require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+				require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+			}
+
+			cfg := &config.Config{}
+			cfg.Input.Business.Company = "Acme"
+
+			project := &config.Project{Name: "my-webapi"}
+			project.Directory.WorkingDir = projectDir
+			project.Settings.Render = table.render
+
+			scaffold := New(cfg, log.New())
+			err := scaffold.renderTemplates(project)
+			require.NoError(t, err)
+
+			for name, content := range table.expected {
+				data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(name)))
+				require.NoError(t, err)
+				assert.Equal(t, content, string(data), "Content of '%s' is incorrect", name)
+			}
+
+			for _, name := range table.missing {
+				assert.NoFileExists(t, filepath.Join(projectDir, filepath.FromSlash(name)))
+			}
+		})
+	}
+}
+
+func TestRenderTemplatesError(t *testing.T) {
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "README.md.tmpl"), []byte("{{ .Missing }}"), 0644))
+
+	project := &config.Project{}
+	project.Directory.WorkingDir = projectDir
+	project.Settings.Render = config.Render{Enabled: true}
+
+	scaffold := New(&config.Config{}, log.New())
+	err := scaffold.renderTemplates(project)
+
+	assert.ErrorContains(t, err, "README.md.tmpl")
+}
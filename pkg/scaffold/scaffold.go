@@ -5,15 +5,17 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	cp "github.com/otiai10/copy"
 
+	"github.com/Ensono/stacks-cli/internal/constants"
 	"github.com/Ensono/stacks-cli/internal/models"
 	"github.com/Ensono/stacks-cli/internal/util"
 	"github.com/Ensono/stacks-cli/pkg/config"
 	"github.com/Ensono/stacks-cli/pkg/downloaders"
-	"github.com/Ensono/stacks-cli/pkg/interfaces"
 	"github.com/go-git/go-billy/v5"
 	"github.com/go-git/go-billy/v5/osfs"
 	"github.com/sirupsen/logrus"
@@ -128,9 +130,7 @@ func (s *Scaffold) PerformOperation(operation config.Operation, project *config.
 
 		// define a replacements object so that all can be passed to the render function
 		// the project is passed in as a seperate object as it is part of a slice
-		replacements := config.Replacements{}
-		replacements.Input = s.Config.Input
-		replacements.Project = *project
+		replacements := config.NewReplacements(s.Config.Input, *project)
 
 		// create a string builder
 		arguments := strings.Builder{}
@@ -151,7 +151,7 @@ func (s *Scaffold) PerformOperation(operation config.Operation, project *config.
 		command = operation.Command
 
 		// run the arguments that have been specified through the template engine
-		args, err := s.Config.RenderTemplate("arguments", operation.Arguments, replacements)
+		args, err := s.Config.RenderTemplateWithOptions("arguments", operation.Arguments, replacements, project.Settings.GetRenderOptions(false))
 		if err != nil {
 			s.Logger.Errorf("Error resolving template: %s", err.Error())
 			return err
@@ -160,11 +160,21 @@ func (s *Scaffold) PerformOperation(operation config.Operation, project *config.
 		// expand any OS based variables on the template
 		arguments.WriteString(os.ExpandEnv(args))
 
-		// if properties have nee supplied and the operation has the flag set to apply the properties
-		// to the command, add them in here
-		if operation.ApplyProperties && len(project.Framework.Properties) > 0 {
+		// if properties have been supplied and the operation has the flag set to apply the properties
+		// to the command, add them in here using the style of the operation or the framework
+		if operation.ApplyProperties && !project.Framework.Properties.IsEmpty() {
+			style := operation.PropertyStyle
+			if style == "" {
+				style = cmdList.PropertyStyle
+			}
+
+			props, err := project.Framework.Properties.Args(style)
+			if err != nil {
+				return err
+			}
+
 			arguments.WriteString(" ")
-			arguments.WriteString(strings.Join(project.Framework.Properties, " "))
+			arguments.WriteString(strings.Join(props, " "))
 		}
 
 		// Execute the command and check that it worked
@@ -175,15 +185,36 @@ func (s *Scaffold) PerformOperation(operation config.Operation, project *config.
 	case "copy":
 
 		// copy the repository from the cloned directory to the project working directory
-		// do not copy the git configuration folder
+		// do not copy the git configuration folder or any files that have been ignored
+		skip, err := s.copySkipFunc(operation, cloneDir)
+		if err != nil {
+			return err
+		}
+
+		// rename files and directories using the rules of the operation and, if it has
+		// been turned on, any template tokens in their names
+		replacements := config.NewReplacements(s.Config.Input, *project)
+
+		rename, err := newRenamer(s.Config, operation.Rename, operation.RenderNames, replacements, project.Settings.GetRenderOptions(true))
+		if err != nil {
+			return err
+		}
+
 		opt := cp.Options{
-			Skip: func(info os.FileInfo, src, dest string) (bool, error) {
-				return strings.HasSuffix(src, ".git"), nil
-			},
+			Skip:              skip,
+			RenameDestination: rename.Destination(cloneDir, path),
 		}
-		err := cp.Copy(cloneDir, path, opt)
+		err = cp.Copy(cloneDir, path, opt)
+		rename.Cleanup()
+
 		if err != nil {
-			s.Logger.Errorf("Issue copying files: %s", err.Error())
+			return fmt.Errorf("issue copying files: %s", err.Error())
+		}
+
+		// directories are traversed to find the included files, so remove those that
+		// did not contain any
+		if len(operation.Include) > 0 {
+			removeEmptyDirs(cloneDir, path, rename)
 		}
 
 	}
@@ -191,6 +222,93 @@ func (s *Scaffold) PerformOperation(operation config.Operation, project *config.
 	return nil
 }
 
+// copySkipFunc returns the function that determines which files are not copied from the
+// template into the project. Files are skipped if they are matched by the `.stacksignore` file
+// in the root of the template or by the exclude patterns of the operation. If include patterns
+// have been set, files that do not match any of them are also skipped
+func (s *Scaffold) copySkipFunc(operation config.Operation, cloneDir string) (func(os.FileInfo, string, string) (bool, error), error) {
+
+	ignore, err := util.ReadIgnoreFile(filepath.Join(cloneDir, constants.IgnoreFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s file: %s", constants.IgnoreFile, err.Error())
+	}
+
+	exclude := util.NewIgnoreMatcher(operation.Exclude)
+	include := util.NewIgnoreMatcher(operation.Include)
+
+	skip := func(info os.FileInfo, src, dest string) (bool, error) {
+
+		if strings.HasSuffix(src, ".git") {
+			return true, nil
+		}
+
+		rel, err := filepath.Rel(cloneDir, src)
+		if err != nil {
+			return false, err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if rel == "." {
+			return false, nil
+		}
+
+		if rel == constants.IgnoreFile || ignore.Match(rel, info.IsDir()) || exclude.Match(rel, info.IsDir()) {
+			s.Logger.Debugf("Not copying ignored path: %s", rel)
+			return true, nil
+		}
+
+		// directories are always traversed so that included files within them are found,
+		// any that are left empty are removed after the copy
+		if !include.Empty() && !info.IsDir() && !include.Match(rel, false) {
+			s.Logger.Debugf("Not copying path that is not included: %s", rel)
+			return true, nil
+		}
+
+		return false, nil
+	}
+
+	return skip, nil
+}
+
+// removeEmptyDirs removes the directories in the project that have been copied from the
+// template and are empty. The deepest directories are removed first so that their parents
+// can be removed
+func removeEmptyDirs(cloneDir string, path string, rename *renamer) {
+
+	var dirs []string
+
+	_ = filepath.Walk(cloneDir, func(src string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() || src == cloneDir {
+			return nil
+		}
+
+		if info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		rel, err := filepath.Rel(cloneDir, src)
+		if err != nil {
+			return nil
+		}
+
+		renamed, err := rename.Path(rel)
+		if err != nil {
+			return nil
+		}
+
+		dirs = append(dirs, filepath.Join(path, renamed))
+		return nil
+	})
+
+	sort.Slice(dirs, func(i, j int) bool {
+		return len(dirs[i]) > len(dirs[j])
+	})
+
+	for _, dir := range dirs {
+		_ = os.Remove(dir)
+	}
+}
+
 // analyseMissing takes the list of missing commands and returns a string
 // to be output to the console if there are any missing commands
 func (s *Scaffold) analyseMissing(missing []models.Command) string {
@@ -249,35 +367,28 @@ func (s *Scaffold) processProject(project config.Project) {
 		s.Logger.Warn(msg)
 	}
 
-	// download the template using the appropriate action
-	var downloader interfaces.Downloader
-	switch packageInfo.Type {
-	case "git":
-
-		// check that the URL is valid, if not skip this project and move onto the next one
-		_, err = url.ParseRequestURI(packageInfo.URL)
-		if err != nil {
-			s.Logger.Errorf("Unable to download framework option as URL is invalid: %s", err.Error())
-			return
-		}
+	// read the token, which may be a reference to where it is stored
+	token, err := s.Config.Input.Options.Token.Resolve()
+	if err != nil {
+		s.Logger.Errorf("Unable to read token: %s", err.Error())
+		return
+	}
 
-		downloader = downloaders.NewGitDownloader(
-			packageInfo.URL,
-			packageInfo.Version,
-			project.Framework.Version,
-			s.Config.Input.Directory.TempDir,
-			s.Config.Input.Options.Token,
-		)
-	case "nuget":
-		downloader = downloaders.NewNugetDownloader(
-			packageInfo.Name,
-			packageInfo.ID,
-			project.Framework.Version,
-			s.Config.Input.Directory.CacheDir,
-			s.Config.Input.Directory.TempDir,
-		)
-	case "filesystem", "local":
-		downloader = downloaders.NewFilesystemDownloader(packageInfo.Path, s.Config.Input.Directory.TempDir)
+	// create the downloader for the type of package
+	downloader, err := downloaders.New(downloaders.PackageRequest{
+		Name:             key,
+		Package:          packageInfo,
+		FrameworkVersion: project.Framework.Version,
+		CacheDir:         s.Config.Input.Directory.CacheDir,
+		TempDir:          s.Config.Input.Directory.TempDir,
+		GitHubAPI:        packageInfo.GetGitHubAPI(s.Config.Input.Options),
+		Token:            token,
+		NoVerify:         s.Config.Input.Options.NoVerify,
+		Registries:       s.Config.Input.Options.Registries,
+	})
+	if err != nil {
+		s.Logger.Errorf("Unable to download framework option: %s", err.Error())
+		return
 	}
 
 	downloader.SetLogger(s.Logger)
@@ -306,6 +417,13 @@ func (s *Scaffold) processProject(project config.Project) {
 		return
 	}
 
+	// check the variables against the parameters that the project needs
+	err = s.resolveParameters(&project)
+	if err != nil {
+		s.Logger.Errorf("Unable to process project: %s", err.Error())
+		return
+	}
+
 	// check to see if any framework commands have been set and check the
 	// version if they have
 	incorrect, info := project.Settings.CheckCmdVersions(s.Config, s.Logger, project.Directory.WorkingDir, project.Directory.TempDir)
@@ -347,6 +465,17 @@ func (s *Scaffold) processProject(project config.Project) {
 			// output information about the operation being performed
 			s.Logger.Info(op.Description)
 
+			// determine if the condition on the operation allows it to run
+			run, err := s.shouldRunWhen(op, &project)
+			if err != nil {
+				s.Logger.Errorf("issue encountered performing '%s' operation: %s", phase.Name, err.Error())
+				break
+			}
+			if !run {
+				s.Logger.Infof("Skipping operation as condition is not met: %s", op.When)
+				continue
+			}
+
 			// determine if this operation should be run by checking the tags
 			if s.shouldRun(op.Tags, project.Framework.Option) {
 				// perform the operation
@@ -363,6 +492,14 @@ func (s *Scaffold) processProject(project config.Project) {
 		}
 	}
 
+	// render any template files in the project, the project is not configured any further
+	// if they cannot be rendered as it would contain files that have not been rendered
+	err = s.renderTemplates(&project)
+	if err != nil {
+		s.Logger.Errorf("Unable to process project, issue rendering template files: %s", err.Error())
+		return
+	}
+
 	// configure the pipeline in the project
 	s.configurePipeline(&project)
 
@@ -423,11 +560,15 @@ func (s *Scaffold) configurePipeline(project *config.Project) {
 		// set the logger on the pipeline settings object
 		pipelineSettings.SetLogger(s.Logger)
 
+		// use the delimiters and strict mode of the project unless the pipeline overrides them
+		pipelineSettings.Delimiters = pipelineSettings.Delimiters.Or(project.Settings.Delimiters)
+		if pipelineSettings.Strict == nil {
+			pipelineSettings.Strict = project.Settings.Strict
+		}
+
 		// define the replacements object so that all can be passed to the render function
 		// the project is passed in a separate project as it is part of a slice
-		replacements := config.Replacements{}
-		replacements.Input = s.Config.Input
-		replacements.Project = *project
+		replacements := config.NewReplacements(s.Config.Input, *project)
 
 		// Get the StacksComponent to check if TemplateMode is enabled
 		key := project.Framework.GetMapKey()
@@ -546,3 +687,40 @@ func (s *Scaffold) shouldRun(tags []string, keyword string) bool {
 
 	return result
 }
+
+// shouldRunWhen evaluates the `when` condition of the operation. The condition is a template
+// expression, such as `eq .Properties.auth "true"`, and the operation is run if the result is
+// true. Operations without a condition are always run
+func (s *Scaffold) shouldRunWhen(operation config.Operation, project *config.Project) (bool, error) {
+
+	condition := strings.TrimSpace(operation.When)
+	if condition == "" {
+		return true, nil
+	}
+
+	// wrap the condition in the delimiters if it is a bare expression
+	options := project.Settings.GetRenderOptions(false)
+	left, right := options.Delimiters.Get()
+	if !strings.Contains(condition, left) {
+		condition = fmt.Sprintf("%s %s %s", left, condition, right)
+	}
+
+	replacements := config.NewReplacements(s.Config.Input, *project)
+
+	result, err := s.Config.RenderTemplateWithOptions("when", condition, replacements, options)
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate condition '%s': %s", operation.When, err.Error())
+	}
+
+	result = strings.TrimSpace(result)
+	if result == "" || result == "<no value>" {
+		return false, nil
+	}
+
+	run, err := strconv.ParseBool(result)
+	if err != nil {
+		return false, fmt.Errorf("condition '%s' must evaluate to true or false, got '%s'", operation.When, result)
+	}
+
+	return run, nil
+}
@@ -2,6 +2,7 @@ package scaffold
 
 import (
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
@@ -9,6 +10,8 @@ import (
 	"github.com/Ensono/stacks-cli/pkg/config"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupScaffoldTestCase(t *testing.T) (func(t *testing.T), string) {
@@ -152,3 +155,131 @@ func TestShouldRun(t *testing.T) {
 		}
 	}
 }
+
+func TestShouldRunWhen(t *testing.T) {
+
+	project := config.Project{
+		Name: "myapp",
+		Framework: config.Framework{
+			Type:       "dotnet",
+			Properties: config.NewProperties([]string{"auth=true", "cache=false"}),
+		},
+	}
+
+	tables := []struct {
+		when     string
+		expected bool
+		err      string
+	}{
+		{"", true, ""},
+		{".Properties.auth", true, ""},
+		{".Properties.cache", false, ""},
+		{".Properties.missing", false, ""},
+		{`eq .Properties.auth "true"`, true, ""},
+		{`{{ if eq .Project.Name "other" }}true{{ end }}`, false, ""},
+		{".Project.Name", false, "must evaluate to true or false"},
+		{"eq .Properties.auth", false, "unable to evaluate condition"},
+	}
+
+	s := Scaffold{Config: &config.Config{}}
+
+	for _, table := range tables {
+		result, err := s.shouldRunWhen(config.Operation{When: table.when}, &project)
+
+		if table.err != "" {
+			assert.ErrorContains(t, err, table.err, table.when)
+			continue
+		}
+
+		require.NoError(t, err, table.when)
+		assert.Equal(t, table.expected, result, table.when)
+	}
+}
+
+func TestPerformOperationCopy(t *testing.T) {
+
+	cleanup, tempDir := setupScaffoldTestCase(t)
+	defer cleanup(t)
+
+	// create a template with a .stacksignore file
+	templateDir := filepath.Join(tempDir, "template")
+	files := map[string]string{
+		".stacksignore":          "stackscli.yml\ndocs/\n*.log\n",
+		"stackscli.yml":          "framework: {}",
+		"README.md":              "readme",
+		"debug.log":              "log",
+		"docs/index.md":          "docs",
+		"src/main.tf":            "main",
+		"src/variables.tf":       "variables",
+		"src/tests/main_test.go": "test",
+		".git/config":            "git",
+	}
+	for name, content := range files {
+		file := filepath.Join(templateDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	}
+
+	tables := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{
+			"stacksignore",
+			nil,
+			nil,
+			[]string{"README.md", "src/main.tf", "src/tests/main_test.go", "src/variables.tf"},
+		},
+		{
+			"exclude",
+			nil,
+			[]string{"src/tests/", "variables.tf"},
+			[]string{"README.md", "src/main.tf"},
+		},
+		{
+			"include",
+			[]string{"src/**/*.tf", "*.log"},
+			nil,
+			[]string{"src/main.tf", "src/variables.tf"},
+		},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+
+			projectDir := filepath.Join(tempDir, table.name)
+
+			scaffold := New(&config.Config{}, log.New())
+			operation := config.Operation{
+				Action:  "copy",
+				Include: table.include,
+				Exclude: table.exclude,
+			}
+
+			err := scaffold.PerformOperation(operation, &config.Project{}, projectDir, templateDir)
+			require.NoError(t, err)
+
+			var copied []string
+			err = filepath.Walk(projectDir, func(path string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+
+				// directories that do not contain any of the copied files should not be created
+				if info.IsDir() {
+					entries, err := os.ReadDir(path)
+					assert.NotEmpty(t, entries, "directory '%s' should not be empty", path)
+					return err
+				}
+				rel, _ := filepath.Rel(projectDir, path)
+				copied = append(copied, filepath.ToSlash(rel))
+				return nil
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, table.expected, copied)
+		})
+	}
+}
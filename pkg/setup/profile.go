@@ -0,0 +1,133 @@
+package setup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ensono/stacks-cli/internal/constants"
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/filter"
+)
+
+// ProfileList shows the names of the profiles in the global configuration file, the profile
+// that is in use is marked with an asterisk
+func (s *Setup) ProfileList() error {
+
+	names, err := s.profileNames()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		s.Logger.Infof("No profiles have been created in: %s", s.globalFile())
+		return nil
+	}
+
+	for _, name := range names {
+		marker := " "
+		if strings.EqualFold(name, s.Profile) {
+			marker = "*"
+		}
+		fmt.Fprintf(s.Out, "%s %s\n", marker, name)
+	}
+
+	return nil
+}
+
+// ProfileUse sets the profile that is used by default, by setting it in the global
+// configuration file
+func (s *Setup) ProfileUse(name string) error {
+
+	if _, err := s.findProfile(name); err != nil {
+		return err
+	}
+
+	fs := s.Config.GetFilesystem()
+	file := s.globalFile()
+
+	subset := filter.New()
+	err := subset.ReadFile(fs, file)
+	if err != nil {
+		return fmt.Errorf("unable to read configuration file: %s", err.Error())
+	}
+
+	err = subset.Set(constants.ProfileKey, strings.ToLower(name))
+	if err != nil {
+		return err
+	}
+
+	s.Logger.Infof("Using profile '%s' by default", name)
+
+	return subset.WriteFile(fs, file, 0700)
+}
+
+// ProfileCreate adds the values at the paths in the configuration to the named profile in the
+// global configuration file. The profile is created if it does not exist
+func (s *Setup) ProfileCreate(name string, paths ...string) error {
+
+	if name == "" || strings.ContainsAny(name, ".[]") {
+		return fmt.Errorf("profile name '%s' is not valid, it cannot be empty or contain '.', '[' or ']'", name)
+	}
+
+	if len(paths) == 0 {
+		return fmt.Errorf("no values have been specified for profile '%s'", name)
+	}
+
+	if existing, err := s.findProfile(name); err == nil {
+		s.Logger.Infof("Updating profile '%s' in: %s", existing, s.globalFile())
+		name = existing
+	} else {
+		s.Logger.Infof("Creating profile '%s' in: %s", name, s.globalFile())
+		name = strings.ToLower(name)
+	}
+
+	prefix := fmt.Sprintf("%s.%s", constants.ProfilesKey, name)
+
+	return s.writeValues(s.globalFile(), prefix, 0700, paths)
+}
+
+// globalFile returns the path to the configuration file in the home directory
+func (s *Setup) globalFile() string {
+	return util.GetConfigFilePath(s.Config.Input.Directory.HomeDir)
+}
+
+// profileNames returns the sorted names of the profiles in the global configuration file
+func (s *Setup) profileNames() ([]string, error) {
+
+	// there are no profiles if the global configuration file does not exist
+	if !util.Exists(s.globalFile()) {
+		return nil, nil
+	}
+
+	v, err := readViper(s.Config.GetFilesystem(), s.globalFile())
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range v.GetStringMap(constants.ProfilesKey) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// findProfile returns the name of the profile in the global configuration file, which is
+// matched without case
+func (s *Setup) findProfile(name string) (string, error) {
+
+	names, err := s.profileNames()
+	if err != nil {
+		return "", err
+	}
+
+	for _, existing := range names {
+		if strings.EqualFold(existing, name) {
+			return existing, nil
+		}
+	}
+
+	return "", fmt.Errorf("profile '%s' does not exist in %s", name, s.globalFile())
+}
@@ -0,0 +1,84 @@
+package setup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ensono/stacks-cli/internal/util"
+	"github.com/Ensono/stacks-cli/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+
+	home := t.TempDir()
+	file := util.GetConfigFilePath(home)
+
+	conf := config.Config{
+		Input: config.InputConfig{
+			Directory: config.Directory{
+				HomeDir: home,
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	setup := New(&conf, log.New())
+	setup.Out = &out
+
+	// there are no profiles before the global file exists
+	require.NoError(t, setup.ProfileList())
+	assert.Empty(t, out.String())
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+	require.NoError(t, os.WriteFile(file, []byte("input:\n  business:\n    company: Ensono\n"), 0o644))
+
+	// create two profiles
+	require.NoError(t, conf.SetValues(map[string]string{
+		"input.business.company":          "ClientA",
+		"input.terraform.backend.storage": "clientastate",
+	}))
+	require.NoError(t, setup.ProfileCreate("ClientA", "input.business.company", "input.terraform.backend.storage"))
+
+	require.NoError(t, conf.SetValues(map[string]string{"input.business.company": "ClientB"}))
+	require.NoError(t, setup.ProfileCreate("clientb", "input.business.company"))
+
+	// update an existing profile
+	require.NoError(t, conf.SetValues(map[string]string{"input.business.domain": "core"}))
+	require.NoError(t, setup.ProfileCreate("CLIENTA", "input.business.domain"))
+
+	require.NoError(t, setup.ProfileUse("clientb"))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, `input:
+  business:
+    company: Ensono
+profile: clientb
+profiles:
+  clienta:
+    input:
+      business:
+        company: ClientA
+        domain: core
+      terraform:
+        backend:
+          storage: clientastate
+  clientb:
+    input:
+      business:
+        company: ClientB
+`, string(content))
+
+	setup.Profile = "ClientB"
+	require.NoError(t, setup.ProfileList())
+	assert.Equal(t, "  clienta\n* clientb\n", out.String())
+
+	assert.EqualError(t, setup.ProfileUse("missing"), "profile 'missing' does not exist in "+file)
+	assert.Error(t, setup.ProfileCreate("client.a", "input.business.company"))
+	assert.Error(t, setup.ProfileCreate("clientc"))
+}
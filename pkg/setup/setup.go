@@ -1,39 +1,53 @@
 package setup
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/Ensono/stacks-cli/internal/constants"
+	"github.com/Ensono/stacks-cli/internal/httpclient"
 	"github.com/Ensono/stacks-cli/internal/util"
 	"github.com/Ensono/stacks-cli/pkg/config"
 	"github.com/Ensono/stacks-cli/pkg/filter"
+	"github.com/go-git/go-billy/v5"
+	yaml "github.com/goccy/go-yaml"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 )
 
 type Setup struct {
 	Config *config.Config
 	Logger *logrus.Logger
+
+	// Out is where the output of the list and get commands is written
+	Out io.Writer
+
+	// Profile is the name of the profile that is in use
+	Profile string
 }
 
 func New(conf *config.Config, logger *logrus.Logger) *Setup {
 	return &Setup{
 		Config: conf,
 		Logger: logger,
+		Out:    os.Stdout,
 	}
 }
 
-func (s *Setup) Upsert() error {
+// Upsert adds or updates values in the local, or global, configuration file. Values that
+// are already in the file are kept. The paths are dotted paths to additional values, relative
+// to the configuration, that are written to the file, e.g. `input.project[0].cloud.region`
+func (s *Setup) Upsert(paths ...string) error {
 
 	var err error
 	var path string
 	var perm uint32
-	fs := s.Config.GetFilesystem()
 
 	// configure variable to hold the path to the file after the basedir has been determined
 	var slug []string = []string{constants.ConfigFileDir, fmt.Sprintf("%s.yml", constants.ConfigName)}
@@ -86,13 +100,57 @@ func (s *Setup) Upsert() error {
 
 	s.Logger.Infof("Updating configuration file: %s", path)
 
-	// Filter the configuration object and write out to the file
-	filter := filter.New()
-	filter.Filter(s.Config.Input, append(dotted, "business.company"))
-	err = filter.WriteFile(fs, path, perm)
+	// the values are written under the `input` key as this is where they are read from
+	for _, item := range append(dotted, "business.company") {
+		val, _ := util.GetValueByDottedPath(s.Config.Input, item)
+		if val == "" {
+			continue
+		}
+		paths = append(paths, fmt.Sprintf("input.%s", item))
+	}
+
+	err = s.writeValues(path, "", perm, paths)
 
 	return err
+}
+
+// writeValues writes the values at the paths in the configuration to the file, under the
+// prefix if it has been set. The existing values in the file are kept
+func (s *Setup) writeValues(path string, prefix string, perm uint32, paths []string) error {
 
+	fs := s.Config.GetFilesystem()
+
+	// read the existing file so that the values that are not being updated are kept
+	subset := filter.New()
+	if util.Exists(path) {
+		err := subset.ReadFile(fs, path)
+		if err != nil {
+			return fmt.Errorf("unable to read configuration file: %s", err.Error())
+		}
+	}
+
+	for _, item := range paths {
+		val, err := util.GetValueByDottedPath(s.Config, item)
+		if err != nil {
+			return fmt.Errorf("unable to get value of '%s': %s", item, err.Error())
+		}
+
+		key, err := keyPath(reflect.TypeOf(config.Config{}), item)
+		if err != nil {
+			return fmt.Errorf("unable to update '%s': %s", item, err.Error())
+		}
+
+		if prefix != "" {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+
+		err = subset.Set(key, val)
+		if err != nil {
+			return fmt.Errorf("unable to update '%s': %s", item, err.Error())
+		}
+	}
+
+	return subset.WriteFile(fs, path, perm)
 }
 
 func (s *Setup) GetLatestInternalConfig() error {
@@ -103,13 +161,19 @@ func (s *Setup) GetLatestInternalConfig() error {
 
 	// Get the data from the URL
 	s.Logger.Debugf("Downloading file from: %s", s.Config.Input.Overrides.InternalConfigURL)
-	resp, err := http.Get(s.Config.Input.Overrides.InternalConfigURL)
+	resp, err := httpclient.Default().Get(s.Config.Input.Overrides.InternalConfigURL, "")
 	if err != nil {
 		s.Logger.Errorf("Error downloading file: %s", err.Error())
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode > 299 {
+		err = fmt.Errorf("error downloading file from '%s': %d", s.Config.Input.Overrides.InternalConfigURL, resp.StatusCode)
+		s.Logger.Errorf("Error downloading file: %s", err.Error())
+		return err
+	}
+
 	// create the file
 	// define the path to the file
 	filepath := path.Join(util.GetStacksCLIDir(), "internal_config.yml")
@@ -127,9 +191,210 @@ func (s *Setup) GetLatestInternalConfig() error {
 	return err
 }
 
+// List shows each of the configuration files that the CLI reads, in the order in which
+// they are merged, whether they exist and their contents
 func (s *Setup) List() error {
 
-	var err error
+	fs := s.Config.GetFilesystem()
 
-	return err
+	paths := util.GetConfigFilePaths(s.Config.Input.Directory.HomeDir, s.Config.Input.Directory.WorkingDir, s.Config.Input.Folders)
+	for _, path := range paths {
+
+		if !util.Exists(path) {
+			fmt.Fprintf(s.Out, "%s (not found)\n", path)
+			continue
+		}
+
+		fmt.Fprintf(s.Out, "%s (exists)\n", path)
+
+		data, err := util.ReadFile(fs, path)
+		if err != nil {
+			return fmt.Errorf("unable to read configuration file: %s", err.Error())
+		}
+
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			fmt.Fprintf(s.Out, "  %s\n", line)
+		}
+	}
+
+	return nil
+}
+
+// Get shows the value at the dotted path in the local, or global, configuration file
+func (s *Setup) Get(path string) error {
+
+	input, err := s.readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	val, err := util.GetValueByDottedPath(input, trimInput(path))
+	if err != nil {
+		return fmt.Errorf("unable to get value of '%s': %s", path, err.Error())
+	}
+
+	switch reflect.ValueOf(val).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		data, err := yaml.Marshal(val)
+		if err != nil {
+			return fmt.Errorf("unable to convert value to YAML: %s", err.Error())
+		}
+		fmt.Fprint(s.Out, string(data))
+	default:
+		fmt.Fprintln(s.Out, val)
+	}
+
+	return nil
+}
+
+// Unset removes the value at the dotted path from the local, or global, configuration file
+func (s *Setup) Unset(path string) error {
+
+	key, err := keyPath(reflect.TypeOf(config.InputConfig{}), trimInput(path))
+	if err != nil {
+		return fmt.Errorf("unable to unset '%s': %s", path, err.Error())
+	}
+
+	fs := s.Config.GetFilesystem()
+	file := s.configFile()
+
+	subset := filter.New()
+	err = subset.ReadFile(fs, file)
+	if err != nil {
+		return fmt.Errorf("unable to read configuration file: %s", err.Error())
+	}
+
+	// the values are normally set under the `input` key, but files that have been
+	// written by older versions of the CLI have them at the root
+	if subset.Has("input") {
+		key = fmt.Sprintf("input.%s", key)
+	}
+
+	if !subset.Remove(key) {
+		s.Logger.Warnf("Value is not set in configuration file: %s", path)
+		return nil
+	}
+
+	s.Logger.Infof("Removing '%s' from configuration file: %s", path, file)
+
+	return subset.WriteFile(fs, file, 0755)
+}
+
+// configFile returns the path to the configuration file in the working directory, or
+// the home directory if the global option has been set
+func (s *Setup) configFile() string {
+	if s.Config.Input.Global {
+		return util.GetConfigFilePath(s.Config.Input.Directory.HomeDir)
+	}
+
+	return util.GetConfigFilePath(s.Config.Input.Directory.WorkingDir)
+}
+
+// readConfigFile reads the local, or global, configuration file in the same way as the
+// configuration is read when the CLI starts
+func (s *Setup) readConfigFile() (config.InputConfig, error) {
+
+	var input config.InputConfig
+	file := s.configFile()
+
+	v, err := readViper(s.Config.GetFilesystem(), file)
+	if err != nil {
+		return input, err
+	}
+
+	// read the values from the `input` key if it exists, otherwise from the root of the file
+	if v.IsSet("input") {
+		var output config.Output
+		err = v.Unmarshal(&output, config.DecodeHook())
+		input = output.Input
+	} else {
+		err = v.Unmarshal(&input, config.DecodeHook())
+	}
+
+	if err != nil {
+		return input, fmt.Errorf("unable to read configuration file '%s': %s", file, err.Error())
+	}
+
+	return input, nil
+}
+
+// readViper reads the configuration file into a new viper instance, so that it is read in
+// the same way as when the CLI starts
+func readViper(bfs billy.Filesystem, file string) (*viper.Viper, error) {
+
+	data, err := util.ReadFile(bfs, file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration file: %s", err.Error())
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	err = v.ReadConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file '%s': %s", file, err.Error())
+	}
+
+	return v, nil
+}
+
+// trimInput removes the `input` prefix from the path, if it has been set, as the paths are
+// relative to the input configuration
+func trimInput(path string) string {
+	if strings.HasPrefix(strings.ToLower(path), "input.") {
+		return path[len("input."):]
+	}
+
+	return path
+}
+
+// keyPath returns the path to the value in the configuration file for the dotted path of fields
+// in the type. The fields are matched without case and the names are taken from the mapstructure
+// tags. Indexes of items in lists and keys of maps are returned as they are
+func keyPath(t reflect.Type, path string) (string, error) {
+
+	segments, err := util.ParseDottedPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	keys := []string{}
+	for i, segment := range segments {
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() == reflect.Map {
+			for _, key := range segments[i:] {
+				keys = append(keys, key.String())
+			}
+			break
+		}
+
+		if t.Kind() != reflect.Struct {
+			return "", fmt.Errorf("'%s' does not have any fields", strings.Join(keys, "."))
+		}
+
+		field, ok := util.GetStructField(t, segment.Name)
+		if !ok {
+			return "", fmt.Errorf("unknown field: %s", segment.Name)
+		}
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		t = field.Type
+		if segment.HasIndex() {
+			if t.Kind() != reflect.Slice {
+				return "", fmt.Errorf("'%s' is not a list", segment.Name)
+			}
+			t = t.Elem()
+		}
+
+		keys = append(keys, util.PathSegment{Name: name, Index: segment.Index}.String())
+	}
+
+	return strings.Join(keys, "."), nil
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Ensono/stacks-cli/internal/util"
 	"github.com/Ensono/stacks-cli/pkg/config"
+	"github.com/go-git/go-billy/v5/osfs"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -131,3 +132,188 @@ func TestCreateProjectSettings(t *testing.T) {
 	}
 
 }
+
+func TestListGetUnset(t *testing.T) {
+
+	cleanup, tempDir := setupSetupTestCase(t)
+	defer cleanup(t)
+
+	home := path.Join(tempDir, "home")
+	working := path.Join(tempDir, "work")
+
+	// write a global file with values at the root and a local file with values under input
+	err := util.WriteFile(osfs.New("/"), util.GetConfigFilePath(home), []byte("business:\n  company: ensono\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = util.WriteFile(osfs.New("/"), util.GetConfigFilePath(working), []byte(`input:
+  business:
+    company: local
+    domain: core
+  terraform:
+    backend:
+      group: tfstate
+  variables:
+    region: westeurope
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.Config{
+		Input: config.InputConfig{
+			Directory: config.Directory{
+				HomeDir:    home,
+				WorkingDir: working,
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	setup := New(&conf, log.New())
+	setup.Out = &out
+
+	// list the files
+	err = setup.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := out.String()
+	for _, expected := range []string{
+		fmt.Sprintf("%s (exists)\n  business:\n    company: ensono\n", util.GetConfigFilePath(home)),
+		fmt.Sprintf("%s (exists)\n  input:\n", util.GetConfigFilePath(working)),
+		fmt.Sprintf("%s (not found)\n", util.GetConfigFilePath(tempDir)),
+	} {
+		if !strings.Contains(list, expected) {
+			t.Errorf("List output should contain '%s':\n%s", expected, list)
+		}
+	}
+
+	// get values from the local and the global file
+	tables := []struct {
+		global bool
+		path   string
+		test   string
+	}{
+		{false, "business.company", "local\n"},
+		{false, "input.Terraform.Backend.Group", "tfstate\n"},
+		{false, "business", "company: local\ndomain: core\n"},
+		{true, "business.company", "ensono\n"},
+	}
+
+	for _, table := range tables {
+		out.Reset()
+		conf.Input.Global = table.global
+
+		err = setup.Get(table.path)
+		if err != nil {
+			t.Errorf("Unable to get '%s': %s", table.path, err.Error())
+			continue
+		}
+
+		if out.String() != table.test {
+			t.Errorf("Value of '%s' should be '%s' not '%s'", table.path, table.test, out.String())
+		}
+	}
+
+	conf.Input.Global = false
+	if err = setup.Get("business.unknown"); err == nil {
+		t.Error("Getting an unknown field should return an error")
+	}
+
+	// unset values in the local file
+	for _, key := range []string{"terraform.backend.group", "variables.region", "business.domain"} {
+		err = setup.Unset(key)
+		if err != nil {
+			t.Errorf("Unable to unset '%s': %s", key, err.Error())
+		}
+	}
+
+	content, err := os.ReadFile(util.GetConfigFilePath(working))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "input:\n  business:\n    company: local\n" {
+		t.Errorf("Unset values and empty parents should be removed from the file:\n%s", content)
+	}
+
+	if err = setup.Unset("business.unknown"); err == nil {
+		t.Error("Unsetting an unknown field should return an error")
+	}
+
+	// unset a value in the global file, which is not under the input key
+	conf.Input.Global = true
+	err = setup.Unset("business.company")
+	if err != nil {
+		t.Error(err)
+	}
+
+	content, _ = os.ReadFile(util.GetConfigFilePath(home))
+	if string(content) != "{}\n" {
+		t.Errorf("Global file should be empty: %s", content)
+	}
+}
+
+func TestUpsertPaths(t *testing.T) {
+
+	cleanup, tempDir := setupSetupTestCase(t)
+	defer cleanup(t)
+
+	// write an existing file, the values in which should be kept
+	file := util.GetConfigFilePath(tempDir)
+	err := util.WriteFile(osfs.New("/"), file, []byte("input:\n  business:\n    domain: core\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := config.Config{
+		Input: config.InputConfig{
+			Directory: config.Directory{
+				WorkingDir: tempDir,
+			},
+			Business: config.Business{
+				Company: "ensono",
+			},
+		},
+	}
+
+	err = conf.SetValues(map[string]string{
+		"input.project[0].cloud.region": "westeurope",
+		"input.options.dryrun":          "true",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setup := New(&conf, log.New())
+	err = setup.Upsert("input.project[0].cloud.region", "input.Options.DryRun")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `input:
+  business:
+    company: ensono
+    domain: core
+  options:
+    dryrun: true
+  project:
+  - cloud:
+      region: westeurope
+`
+	if string(content) != expected {
+		t.Errorf("File should contain the existing and updated values:\n%s", content)
+	}
+
+	if err = setup.Upsert("input.project[0].unknown"); err == nil {
+		t.Error("Updating an unknown path should return an error")
+	}
+}